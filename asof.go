@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Release date fields and the status boolean each of them drives.
+//
+//nolint:gochecknoglobals // ok
+var statusFields = []struct{ date, flag string }{
+	{"ltsFrom", "isLts"},
+	{"eoasFrom", "isEoas"},
+	{"eolFrom", "isEol"},
+	{"eoesFrom", "isEoes"},
+	{"discontinuedFrom", "isDiscontinued"},
+}
+
+// now is the client's clock: the --as-of date if given, the current time otherwise.
+func (c *client) now() time.Time {
+	if c.asOfTime.IsZero() {
+		return time.Now()
+	}
+
+	return c.asOfTime
+}
+
+// fetchAsOf fetches path, with the statuses of its releases as of --as-of.
+// It is the one place --as-of is applied, so that the commands deriving
+// their response from the statuses (compare, suggest, scan, ...) see them.
+func (c *client) fetchAsOf(path string) (body []byte, err error) {
+	if body, err = c.fetch(path); err != nil || c.asOfTime.IsZero() {
+		return
+	}
+
+	return applyAsOf(body, c.asOfTime)
+}
+
+// applyAsOf recomputes the status booleans of every release found in the
+// response, as they will be (or were) at the given date.
+func applyAsOf(response []byte, at time.Time) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(response, &v); err != nil {
+		return nil, err //nolint:wrapcheck // ok
+	}
+
+	walkReleases(v, func(rel map[string]any) { recomputeStatus(rel, at) })
+
+	return json.Marshal(v) //nolint:wrapcheck // ok
+}
+
+// walkReleases calls fn for every release object (anything carrying
+// an isEol flag) nested in v.
+func walkReleases(v any, fn func(rel map[string]any)) {
+	switch x := v.(type) {
+	case map[string]any:
+		if _, ok := x["isEol"]; ok {
+			fn(x)
+		}
+
+		for _, y := range x {
+			walkReleases(y, fn)
+		}
+	case []any:
+		for _, y := range x {
+			walkReleases(y, fn)
+		}
+	}
+}
+
+// recomputeStatus sets the status booleans of rel from its raw dates, as of
+// the given date. Flags whose date is unknown are left as the API set them.
+// A release is maintained until its EOL, or its extended support end if any.
+func recomputeStatus(rel map[string]any, at time.Time) {
+	for _, sf := range statusFields {
+		d, err := time.Parse(time.DateOnly, getString(rel, sf.date))
+		if err != nil {
+			continue
+		}
+
+		rel[sf.flag] = !at.Before(d)
+	}
+
+	if getString(rel, "eolFrom") == "" {
+		return
+	}
+
+	isEol, _ := rel["isEol"].(bool)    //nolint:errcheck // ok
+	isEoes, ok := rel["isEoes"].(bool) //nolint:errcheck // ok
+	rel["isMaintained"] = !isEol || (ok && !isEoes)
+}
+
+// Release statuses, as reported by releaseStatus.
+const (
+	StatusEol          = "eol"
+	StatusEoas         = "eoas"
+	StatusMaintained   = "maintained"
+	StatusUnmaintained = "unmaintained"
+)
+
+// releaseStatus summarizes the status booleans of rel in a single word.
+func releaseStatus(rel map[string]any) string {
+	flag := func(key string) bool { b, _ := rel[key].(bool); return b } //nolint:errcheck // ok
+
+	switch {
+	case flag("isEol"):
+		return StatusEol
+	case flag("isEoas"):
+		return StatusEoas
+	case flag("isMaintained"):
+		return StatusMaintained
+	default:
+		return StatusUnmaintained
+	}
+}
+
+// releaseLink returns the link to the latest patch of rel, if any.
+func releaseLink(rel map[string]any) string {
+	latest, _ := rel["latest"].(map[string]any) //nolint:errcheck // ok
+	return getString(latest, "link")
+}
+
+// dateWithin reports whether date (YYYY-MM-DD) falls after now, within window.
+func dateWithin(now time.Time, window time.Duration, date string) bool {
+	t, err := time.Parse(time.DateOnly, date)
+	return err == nil && t.After(now) && t.Before(now.Add(window))
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRecomputeStatus(t *testing.T) {
+	t.Parallel()
+
+	rel := func() map[string]any {
+		return map[string]any{
+			"name": "22.04", "ltsFrom": nil, "isLts": true,
+			"eoasFrom": "2024-09-30", "isEoas": true,
+			"eolFrom": "2027-04-01", "isEol": false,
+			"eoesFrom": "2032-04-09", "isEoes": false,
+			"isMaintained": true,
+		}
+	}
+
+	tests := []struct {
+		at  string
+		exp map[string]bool
+	}{
+		{"2024-01-01", map[string]bool{"isLts": true, "isEoas": false, "isEol": false, "isEoes": false, "isMaintained": true}},
+		{"2024-09-30", map[string]bool{"isLts": true, "isEoas": true, "isEol": false, "isEoes": false, "isMaintained": true}},
+		{"2027-04-01", map[string]bool{"isLts": true, "isEoas": true, "isEol": true, "isEoes": false, "isMaintained": true}},
+		{"2033-01-01", map[string]bool{"isLts": true, "isEoas": true, "isEol": true, "isEoes": true, "isMaintained": false}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.at, func(t *testing.T) {
+			t.Parallel()
+
+			at, _ := time.Parse(time.DateOnly, tc.at)
+			r := rel()
+			recomputeStatus(r, at)
+
+			for k, v := range tc.exp {
+				if r[k] != v {
+					t.Fatalf("expected %s to be %v, got %v", k, v, r[k])
+				}
+			}
+		})
+	}
+}
+
+func TestApplyAsOf(t *testing.T) {
+	t.Parallel()
+
+	in := []byte(`{"result":{"name":"go","releases":[{"name":"1.24","eolFrom":"2026-02-11","isEol":false,` +
+		`"isMaintained":true},{"name":"1.0","eolFrom":null,"isEol":true,"isMaintained":false}]}}`)
+	at, _ := time.Parse(time.DateOnly, "2026-03-01")
+
+	out, err := applyAsOf(in, at)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var got struct {
+		Result struct {
+			Releases []struct {
+				IsEol        bool `json:"isEol"`
+				IsMaintained bool `json:"isMaintained"`
+			} `json:"releases"`
+		} `json:"result"`
+	}
+
+	if err = json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i, r := range got.Result.Releases {
+		if !r.IsEol || r.IsMaintained {
+			t.Fatalf("expected release %d to be EOL and unmaintained, got %+v", i, r)
+		}
+	}
+
+	if _, err = applyAsOf([]byte("{"), at); err == nil {
+		t.Fatal("expected an error for invalid JSON")
+	}
+}
+
+func TestClientAsOfTemplateClock(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		asOf, exp string
+	}{
+		{"2025-08-01", "true false"},
+		{"2025-06-01", "false false"},
+		{"2025-09-01", "false true"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.asOf, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient([]string{
+				"release", "go", "1.23", "--as-of", tc.asOf,
+				"-t", `{{eolWithin "30d" .eolFrom}} {{.isEol}}`,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.httpClient = buf, &mockHTTPClient{}
+
+			if err = c.handle(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if x := buf.String(); x != tc.exp {
+				t.Fatalf("expected %q, got %q", tc.exp, x)
+			}
+		})
+	}
+}
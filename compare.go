@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// compareSide is one of the two releases being compared.
+type compareSide struct {
+	SupportDaysLeft *int   `json:"supportDaysLeft"`
+	Product         string `json:"product"`
+	Release         string `json:"release"`
+	Label           string `json:"label"`
+	ReleaseDate     string `json:"releaseDate"`
+	EoasFrom        string `json:"eoasFrom"`
+	EolFrom         string `json:"eolFrom"`
+	Latest          string `json:"latest"`
+	Status          string `json:"status"`
+	IsLts           bool   `json:"isLts"`
+	IsEoas          bool   `json:"isEoas"`
+	IsEol           bool   `json:"isEol"`
+	IsMaintained    bool   `json:"isMaintained"`
+}
+
+// compareRow is one line of the side by side comparison.
+type compareRow struct {
+	Field string `json:"field"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+	Diff  bool   `json:"diff"`
+}
+
+// compareSpecs parses the compare arguments: either "product v1 v2" or
+// "product1:v1 product2:v2", into [product, version] pairs.
+func compareSpecs(args []string) (specs [2][2]string, err error) {
+	switch {
+	case len(args) == 3 && !strings.Contains(args[0], ":"): //nolint:mnd // product v1 v2
+		specs[0] = [2]string{args[0], args[1]}
+		specs[1] = [2]string{args[0], args[2]}
+	case len(args) == 2 && strings.Contains(args[0], ":") && strings.Contains(args[1], ":"):
+		for i, arg := range args {
+			p, v, _ := strings.Cut(arg, ":")
+			specs[i] = [2]string{p, v}
+		}
+	default:
+		return specs, fmt.Errorf("%w: compare requires <product> <release1> <release2> "+
+			"or <product1>:<release1> <product2>:<release2>", errUsage)
+	}
+
+	for _, spec := range specs {
+		if spec[0] == "" || spec[1] == "" {
+			return specs, fmt.Errorf("%w: compare requires non-empty products and releases", errUsage)
+		}
+	}
+
+	return
+}
+
+func (c *client) compare() (err error) {
+	specs, err := compareSpecs(c.args)
+	if err != nil {
+		return
+	}
+
+	var sides [2]compareSide
+
+	for i, spec := range specs {
+		var rel map[string]any
+
+		err = c.withProduct(spec[0], func(pn string) (err error) {
+			rel, _, err = c.lookupRelease(pn, spec[1])
+			spec[0] = pn
+
+			return
+		})
+		if err != nil {
+			return
+		}
+
+		sides[i] = newCompareSide(spec[0], rel, c.now())
+	}
+
+	c.response, err = json.Marshal(map[string]any{"result": map[string]any{
+		"left": sides[0], "right": sides[1], "rows": compareRows(sides[0], sides[1]),
+	}})
+
+	return
+}
+
+func newCompareSide(product string, rel map[string]any, now time.Time) (side compareSide) {
+	latest, _ := rel["latest"].(map[string]any)                         //nolint:errcheck // ok
+	flag := func(key string) bool { b, _ := rel[key].(bool); return b } //nolint:errcheck // ok
+
+	side = compareSide{
+		Product:      product,
+		Release:      getString(rel, "name"),
+		Label:        getString(rel, "label"),
+		ReleaseDate:  getString(rel, "releaseDate"),
+		EoasFrom:     getString(rel, "eoasFrom"),
+		EolFrom:      getString(rel, "eolFrom"),
+		Latest:       getString(latest, "name"),
+		IsLts:        flag("isLts"),
+		IsEoas:       flag("isEoas"),
+		IsEol:        flag("isEol"),
+		IsMaintained: flag("isMaintained"),
+		Status:       releaseStatus(rel),
+	}
+
+	side.SupportDaysLeft = daysUntil(now, side.EolFrom)
+
+	return
+}
+
+// daysUntil returns the number of days left until date (YYYY-MM-DD),
+// zero if it's already past, or nil if date is not a valid date.
+func daysUntil(now time.Time, date string) *int {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return nil
+	}
+
+	days := max(0, int(t.Sub(now.Truncate(24*time.Hour)).Hours()/24)) //nolint:mnd // ok
+
+	return &days
+}
+
+func compareRows(a, b compareSide) (rows []compareRow) {
+	orNA := func(s string) string {
+		if s == "" {
+			return "n/a"
+		}
+
+		return s
+	}
+
+	daysLeft := func(s compareSide) string {
+		if s.SupportDaysLeft == nil {
+			return "unknown"
+		}
+
+		return strconv.Itoa(*s.SupportDaysLeft)
+	}
+
+	status := func(s compareSide) string {
+		if s.Status == StatusEol || s.Status == StatusEoas {
+			return strings.ToUpper(s.Status)
+		}
+
+		return s.Status
+	}
+
+	for _, r := range [][3]string{
+		{"Release", a.Product + " " + a.Release, b.Product + " " + b.Release},
+		{"Label", a.Label, b.Label},
+		{"Release Date", orNA(a.ReleaseDate), orNA(b.ReleaseDate)},
+		{"LTS", strconv.FormatBool(a.IsLts), strconv.FormatBool(b.IsLts)},
+		{"EOAS", orNA(a.EoasFrom), orNA(b.EoasFrom)},
+		{"EOL", orNA(a.EolFrom), orNA(b.EolFrom)},
+		{"Status", status(a), status(b)},
+		{"Support Days Left", daysLeft(a), daysLeft(b)},
+		{"Latest Patch", orNA(a.Latest), orNA(b.Latest)},
+	} {
+		rows = append(rows, compareRow{Field: r[0], Left: r[1], Right: r[2], Diff: r[1] != r[2]})
+	}
+
+	return
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareSpecs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args   string
+		exp    [2][2]string
+		expErr error
+	}{
+		{"ubuntu 22.04 24.04", [2][2]string{{"ubuntu", "22.04"}, {"ubuntu", "24.04"}}, nil},
+		{"postgresql:15 mysql:8.0", [2][2]string{{"postgresql", "15"}, {"mysql", "8.0"}}, nil},
+		{"postgresql:15 mysql", [2][2]string{}, errUsage},
+		{"postgresql:15 :8.0", [2][2]string{}, errUsage},
+		{"ubuntu 22.04", [2][2]string{}, errUsage},
+		{"a b c d", [2][2]string{}, errUsage},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.args, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := compareSpecs(strings.Fields(tc.args))
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("expected error %v, got %v", tc.expErr, err)
+			}
+
+			if err == nil && got != tc.exp {
+				t.Fatalf("expected %v, got %v", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestNewCompareSide(t *testing.T) {
+	t.Parallel()
+
+	now, _ := time.Parse(time.DateOnly, "2027-03-01")
+	rel := map[string]any{
+		"name": "22.04", "eolFrom": "2027-04-01", "isEol": false, "isMaintained": true,
+		"latest": map[string]any{"name": "22.04.5"},
+	}
+
+	side := newCompareSide("ubuntu", rel, now)
+	if side.SupportDaysLeft == nil || *side.SupportDaysLeft != 31 || side.Latest != "22.04.5" {
+		t.Fatalf("Unexpected compare side: %+v", side)
+	}
+
+	rel["eolFrom"] = "2020-01-01"
+	if side = newCompareSide("ubuntu", rel, now); *side.SupportDaysLeft != 0 {
+		t.Fatalf("Expected no support days left, got %d", *side.SupportDaysLeft)
+	}
+
+	rel["eolFrom"] = nil
+	if side = newCompareSide("ubuntu", rel, now); side.SupportDaysLeft != nil {
+		t.Fatalf("Expected unknown support days left, got %d", *side.SupportDaysLeft)
+	}
+}
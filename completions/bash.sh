@@ -27,10 +27,10 @@ _eol_completion() {
     _init_completion || return
 
     # Main commands
-    local commands="index products products-full product release release-badge latest categories category tags tag identifiers identifier templates-export completion completion-bash completion-zsh version help"
+    local commands="index products products-full product search release release-badge latest suggest compare diff watch digest inventory scan host os hook serve-api mcp upcoming categories category tags tag identifiers identifier templates-export completion completion-bash completion-zsh version help"
 
     # Global flags
-    local global_flags="-f --format -t --template --templates-dir -h --help"
+    local global_flags="-f --format -t --template --templates-dir --require-latest-patch --as-of --since --config --interval --state --once --smtp-addr --smtp-user --smtp-from --smtp-to --dry-run --inventory --owner --env --notes --group-by --kube-version --timeout --listen --cache-ttl --apply --within --from --to --category --tag -q --quiet -v --verbose --debug -h --help"
 
     case ${cword} in
         1)
@@ -51,7 +51,7 @@ _eol_completion() {
                     compgen_output=$(compgen -W "${products[*]}" -- "${cur}") || true
                     mapfile -t COMPREPLY <<< "${compgen_output}"
                     ;;
-                category)
+                category|--category)
                     # Complete with actual category names
                     local categories
                     local categories_output
@@ -61,7 +61,7 @@ _eol_completion() {
                     compgen_output=$(compgen -W "${categories[*]}" -- "${cur}") || true
                     mapfile -t COMPREPLY <<< "${compgen_output}"
                     ;;
-                tag)
+                tag|--tag)
                     # Complete with actual tag names
                     local tags
                     local tags_output
@@ -81,7 +81,7 @@ _eol_completion() {
                     compgen_output=$(compgen -W "${types[*]}" -- "${cur}") || true
                     mapfile -t COMPREPLY <<< "${compgen_output}"
                     ;;
-                release|release-badge)
+                release|release-badge|suggest|compare|host)
                     # Complete with product names for release commands
                     local products
                     local products_output
@@ -91,9 +91,30 @@ _eol_completion() {
                     compgen_output=$(compgen -W "${products[*]}" -- "${cur}") || true
                     mapfile -t COMPREPLY <<< "${compgen_output}"
                     ;;
+                inventory)
+                    local compgen_output
+                    compgen_output=$(compgen -W "check report add remove" -- "${cur}") || true
+                    mapfile -t COMPREPLY <<< "${compgen_output}"
+                    ;;
+                scan)
+                    local compgen_output
+                    compgen_output=$(compgen -W "dir deps image k8s terraform" -- "${cur}") || true
+                    mapfile -t COMPREPLY <<< "${compgen_output}"
+                    ;;
+                --group-by)
+                    local compgen_output
+                    compgen_output=$(compgen -W "owner environment" -- "${cur}") || true
+                    mapfile -t COMPREPLY <<< "${compgen_output}"
+                    ;;
                 -f|--format)
                     local compgen_output
-                    compgen_output=$(compgen -W "text json" -- "${cur}") || true
+                    compgen_output=$(compgen -W "text json markdown github gitlab-codequality" -- "${cur}") || true
+                    mapfile -t COMPREPLY <<< "${compgen_output}"
+                    ;;
+                diff|os|hook|--since|--config|--state|--inventory|--apply)
+                    # Complete snapshot files
+                    local compgen_output
+                    compgen_output=$(compgen -f -- "${cur}") || true
                     mapfile -t COMPREPLY <<< "${compgen_output}"
                     ;;
                 --templates-dir)
@@ -111,7 +132,7 @@ _eol_completion() {
             ;;
         3)
             case "${words[1]}" in
-                release|release-badge)
+                release|release-badge|suggest|compare)
                     # Third argument for release commands: complete with versions for the product
                     local product="${words[2]}"
                     if [[ -n "${product}" ]]; then
@@ -69,9 +69,39 @@ _eol() {
     typeset -A opt_args
 
     _arguments -C \
-        '(-f --format)'{-f,--format}'[Output format]:format:(text json)' \
+        '(-f --format)'{-f,--format}'[Output format]:format:(text json markdown github gitlab-codequality)' \
         '(-t --template)'{-t,--template}'[Inline template]:template:' \
         '--templates-dir[Template directory]:directory:_directories' \
+        '--require-latest-patch[Fail if not the latest patch]' \
+        '--as-of[Evaluate statuses as of date]:date (YYYY-MM-DD):' \
+        '--since[Snapshot to diff against]:snapshot:_files' \
+        '--config[Watch list]:watch list:_files' \
+        '--interval[Poll interval]:duration:(1h 12h 24h)' \
+        '--state[Watch state file]:state:_files' \
+        '--once[Poll once and exit]' \
+        '--smtp-addr[SMTP server]:host\:port:' \
+        '--smtp-user[SMTP user]:user:' \
+        '--smtp-from[Digest sender]:address:' \
+        '--smtp-to[Digest recipients]:addresses:' \
+        '--dry-run[Print the digest or changes instead of sending or writing them]' \
+        '--inventory[Inventory file]:inventory:_files' \
+        '--owner[Inventory entry owner]:owner:' \
+        '--env[Inventory entry environment]:environment:' \
+        '--notes[Inventory entry notes]:notes:' \
+        '--group-by[Inventory report grouping]:group:(owner environment)' \
+        '--kube-version[Cluster version]:version:' \
+        '--timeout[Version command timeout]:duration:(5s 10s 30s)' \
+        '--listen[Address to serve on]:host\:port:' \
+        '--cache-ttl[API response cache TTL]:duration:(1h 6h 24h)' \
+        '--apply[File to rewrite to the suggested release]:file:_files' \
+        '--within[Time window]:duration:(30d 90d 6mo 12mo)' \
+        '--from[Window start]:date (YYYY-MM-DD):' \
+        '--to[Window end]:date (YYYY-MM-DD):' \
+        '--category[Category filter]:category:_eol_categories' \
+        '--tag[Tag filter]:tag:_eol_tags' \
+        '(-q --quiet -v --verbose --debug)'{-q,--quiet}'[Only print errors]' \
+        '(-q --quiet -v --verbose --debug)'{-v,--verbose}'[Print informational messages]' \
+        '(-q --quiet -v --verbose --debug)--debug[Print request details]' \
         '(-h --help)'{-h,--help}'[Show help]' \
         '1: :_eol_commands' \
         '*:: :->args'
@@ -79,19 +109,42 @@ _eol() {
     case $state in
         args)
             case $words[1] in
-                product|latest)
+                product|latest|upcoming|host)
                     _eol_products
                     ;;
-                release|release-badge)
+                release|release-badge|suggest|compare)
                     case $CURRENT in
                         2)
                             _eol_products
                             ;;
-                        3)
+                        3|4)
                             _eol_product_versions $words[2]
                             ;;
                     esac
                     ;;
+                diff|os|hook)
+                    _files
+                    ;;
+                scan)
+                    case $CURRENT in
+                        2)
+                            _values 'scan target' dir deps image k8s terraform
+                            ;;
+                        3)
+                            _files
+                            ;;
+                    esac
+                    ;;
+                inventory)
+                    case $CURRENT in
+                        2)
+                            _values 'inventory command' check report add remove
+                            ;;
+                        3)
+                            _eol_products
+                            ;;
+                    esac
+                    ;;
                 category)
                     case $CURRENT in
                         2)
@@ -124,9 +177,23 @@ _eol_commands() {
         'products:List all products'
         'products-full:List all products with detailed information'
         'product:Get details for a specific product'
+        'search:Search products by name, label, alias, tag or category'
         'release:Get specific release information'
         'release-badge:Generate SVG badge for specific release'
         'latest:Get latest release information'
+        'suggest:Suggest releases to upgrade to, optionally applying the next one'
+        'compare:Compare two releases side by side'
+        'diff:Compare two products-full snapshots'
+        'watch:Watch products and notify webhooks about changes'
+        'digest:Email a digest of watched releases needing attention'
+        'inventory:Check, report on or edit the inventory'
+        'scan:Detect pinned versions and their status'
+        'host:Check the versions of the tools installed locally'
+        'os:Show the release of the local (or given) OS'
+        'hook:Fail on EOL versions introduced by the given files (pre-commit)'
+        'serve-api:Serve the API over HTTP, with caching and aggregations'
+        'mcp:Serve the Model Context Protocol over stdio'
+        'upcoming:List releases reaching EOL within a date window'
         'categories:List all categories'
         'category:List products in a specific category'
         'tags:List all tags'
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// snapshot is a /products/full payload, as saved by `eol -f json products-full`.
+type snapshot struct {
+	LastModified string `json:"last_modified"`
+	Result       []any  `json:"result"`
+}
+
+// snapshotDiff lists the lifecycle changes between two snapshots.
+type snapshotDiff struct {
+	OldLastModified string       `json:"oldLastModified"`
+	NewLastModified string       `json:"newLastModified"`
+	AddedProducts   []string     `json:"addedProducts"`
+	RemovedProducts []string     `json:"removedProducts"`
+	AddedReleases   []releaseRef `json:"addedReleases"`
+	RemovedReleases []releaseRef `json:"removedReleases"`
+	DateChanges     []dateChange `json:"dateChanges"`
+	Unchanged       bool         `json:"unchanged"`
+}
+
+type releaseRef struct {
+	Product string `json:"product"`
+	Release string `json:"release"`
+}
+
+// dateChange is a lifecycle date (eolFrom, eoasFrom, ...) that moved.
+type dateChange struct {
+	Product string `json:"product"`
+	Release string `json:"release"`
+	Field   string `json:"field"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+func (c *client) diff() (err error) {
+	var oldSnap, newSnap snapshot
+
+	switch {
+	case c.since != "" && len(c.args) == 0:
+		if oldSnap, err = readSnapshot(c.since); err != nil {
+			return
+		}
+
+		var body []byte
+		if body, err = c.fetch("/products/full"); err != nil {
+			return
+		}
+
+		err = json.Unmarshal(body, &newSnap)
+	case c.since == "" && len(c.args) == 2: //nolint:mnd // old and new
+		if oldSnap, err = readSnapshot(c.args[0]); err != nil {
+			return
+		}
+
+		newSnap, err = readSnapshot(c.args[1])
+	default:
+		return fmt.Errorf("%w: diff requires <old.json> <new.json> or --since <snapshot.json>", errUsage)
+	}
+
+	if err != nil {
+		return
+	}
+
+	c.response, err = json.Marshal(map[string]any{"result": diffSnapshots(oldSnap, newSnap)})
+
+	return
+}
+
+func readSnapshot(fname string) (snap snapshot, err error) {
+	body, err := os.ReadFile(fname) //nolint:gosec // user supplied path, on purpose
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(body, &snap); err != nil {
+		err = fmt.Errorf("invalid snapshot %s: %w", fname, err)
+	}
+
+	return
+}
+
+// diffSnapshots compares two snapshots. When both carry the same
+// last_modified stamp, the data is known to be unchanged and is not compared.
+func diffSnapshots(oldSnap, newSnap snapshot) (d snapshotDiff) {
+	d.OldLastModified, d.NewLastModified = oldSnap.LastModified, newSnap.LastModified
+	if d.OldLastModified != "" && d.OldLastModified == d.NewLastModified {
+		d.Unchanged = true
+		return
+	}
+
+	oldProducts, newProducts := indexByName(oldSnap.Result), indexByName(newSnap.Result)
+
+	for _, name := range sortedKeys(newProducts) {
+		if _, ok := oldProducts[name]; !ok {
+			d.AddedProducts = append(d.AddedProducts, name)
+		}
+	}
+
+	for _, name := range sortedKeys(oldProducts) {
+		newProduct, ok := newProducts[name]
+		if !ok {
+			d.RemovedProducts = append(d.RemovedProducts, name)
+			continue
+		}
+
+		d.diffReleases(name, oldProducts[name], newProduct)
+	}
+
+	d.Unchanged = len(d.AddedProducts)+len(d.RemovedProducts)+len(d.AddedReleases)+
+		len(d.RemovedReleases)+len(d.DateChanges) == 0
+
+	return
+}
+
+func (d *snapshotDiff) diffReleases(product string, oldProduct, newProduct map[string]any) {
+	oldReleases := indexByName(toSlice(oldProduct["releases"]))
+	newReleases := indexByName(toSlice(newProduct["releases"]))
+	byVersion := func(a, b string) int { return cmp.Or(compareVersions(b, a), strings.Compare(a, b)) }
+
+	for _, name := range sortedKeys(newReleases, byVersion) {
+		if _, ok := oldReleases[name]; !ok {
+			d.AddedReleases = append(d.AddedReleases, releaseRef{Product: product, Release: name})
+		}
+	}
+
+	for _, name := range sortedKeys(oldReleases, byVersion) {
+		newRelease, ok := newReleases[name]
+		if !ok {
+			d.RemovedReleases = append(d.RemovedReleases, releaseRef{Product: product, Release: name})
+			continue
+		}
+
+		for _, lf := range lifecycleFields {
+			if o, n := getString(oldReleases[name], lf.field), getString(newRelease, lf.field); o != n {
+				d.DateChanges = append(d.DateChanges, dateChange{
+					Product: product, Release: name, Field: lf.field, Old: o, New: n,
+				})
+			}
+		}
+	}
+}
+
+// indexByName indexes a list of objects (products, releases) by their name.
+func indexByName(xs []any) map[string]map[string]any {
+	idx := make(map[string]map[string]any, len(xs))
+
+	for _, x := range xs {
+		if m, ok := x.(map[string]any); ok {
+			idx[getString(m, "name")] = m
+		}
+	}
+
+	return idx
+}
+
+// sortedKeys returns the keys of m, sorted with the given compare
+// function or lexically if none is given.
+func sortedKeys[T any](m map[string]T, cmpFn ...func(a, b string) int) []string {
+	keys := slices.Collect(maps.Keys(m))
+	if len(cmpFn) == 0 {
+		slices.Sort(keys)
+	} else {
+		slices.SortFunc(keys, cmpFn[0])
+	}
+
+	return keys
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClientDiff(t *testing.T) {
+	t.Parallel()
+
+	old, snew := filepath.Join("testdata", "snapshots", "old.json"), filepath.Join("testdata", "snapshots", "new.json")
+
+	//nolint:govet // ok
+	cases := []struct {
+		args   []string
+		golden string
+		expErr error
+	}{
+		{[]string{"diff", old, snew}, "text", nil},
+		{[]string{"diff", old, snew, "-f", "json"}, "json", nil},
+		{[]string{"diff", old, snew, "-f", "markdown"}, "markdown", nil},
+		{[]string{"diff", old, old}, "unchanged", nil},
+		{[]string{"diff", old}, "", errUsage},
+		{[]string{"diff", old, snew, "--since", old}, "", errUsage},
+		{[]string{"diff", old, "testdata/snapshots/missing.json"}, "", fs.ErrNotExist},
+		{[]string{"diff", "--since", old}, "", nil},
+		{[]string{"index", "-f", "markdown"}, "", errUnsupportedFormat},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.httpClient = buf, &mockHTTPClient{}
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "diff", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected %q, got %q", exp, x)
+			}
+		})
+	}
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	t.Parallel()
+
+	product := func(name string, releases ...any) any {
+		return map[string]any{"name": name, "releases": releases}
+	}
+	release := func(name, eol string) any { return map[string]any{"name": name, "eolFrom": eol} }
+
+	oldSnap := snapshot{Result: []any{product("go", release("1.9", ""), release("1.10", ""))}}
+	newSnap := snapshot{Result: []any{product("go", release("1.11", ""), release("1.10", "2019-02-25"))}}
+
+	d := diffSnapshots(oldSnap, newSnap)
+	if d.Unchanged || len(d.AddedReleases) != 1 || len(d.RemovedReleases) != 1 || len(d.DateChanges) != 1 {
+		t.Fatalf("Unexpected diff: %+v", d)
+	}
+
+	if x := d.DateChanges[0]; x.Release != "1.10" || x.Field != "eolFrom" || x.Old != "" || x.New != "2019-02-25" {
+		t.Fatalf("Unexpected date change: %+v", x)
+	}
+
+	oldSnap.LastModified, newSnap.LastModified = "2025-01-01", "2025-01-01"
+	if d = diffSnapshots(oldSnap, newSnap); !d.Unchanged || len(d.AddedReleases) != 0 {
+		t.Fatalf("Expected unchanged snapshots to be skipped, got %+v", d)
+	}
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// smtpConfig holds the digest mail settings. The --smtp-* flags take
+// precedence, the password can also be set via EOL_SMTP_PASSWORD.
+type smtpConfig struct {
+	Addr     string   `json:"addr"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// digestEntry is a tracked release that needs attention.
+type digestEntry struct {
+	DaysLeft *int   `json:"daysLeft"`
+	Product  string `json:"product"`
+	Release  string `json:"release"`
+	Label    string `json:"label"`
+	Reason   string `json:"reason"`
+	EoasFrom string `json:"eoasFrom,omitempty"`
+	EolFrom  string `json:"eolFrom,omitempty"`
+	Link     string `json:"link,omitempty"`
+}
+
+// Environment variable holding the SMTP password.
+const smtpPasswordEnv = "EOL_SMTP_PASSWORD"
+
+// How long delivering the digest may take, from dialing to QUIT.
+const smtpTimeout = 30 * time.Second
+
+func (c *client) digest() (err error) {
+	cfg, err := readWatchConfig(c.config)
+	if err != nil {
+		return
+	}
+
+	within := cmp.Or(c.within, cfg.Within, defaultWithin)
+
+	window, err := parseExtendedDuration(within)
+	if err != nil {
+		return fmt.Errorf("%w: invalid window: %w", errUsage, err)
+	}
+
+	var entries []digestEntry
+
+	for _, wp := range cfg.Products {
+		var product map[string]any
+		if product, err = c.fetchProduct(wp.Product); err != nil {
+			return
+		}
+
+		entries = append(entries, digestEntries(wp, product, c.now(), window)...)
+	}
+
+	slices.SortStableFunc(entries, func(a, b digestEntry) int {
+		return cmp.Or(strings.Compare(a.EolFrom, b.EolFrom), strings.Compare(a.Product, b.Product),
+			compareVersions(b.Release, a.Release))
+	})
+
+	data := map[string]any{
+		"generatedAt": c.now().Format(time.DateOnly), "within": within,
+		"total": len(entries), "entries": entries,
+	}
+
+	var subject, text, html []byte
+
+	for name, out := range map[string]*[]byte{"digest-subject": &subject, "digest-text": &text} {
+		if *out, err = c.renderTemplate(name, data); err != nil {
+			return
+		}
+	}
+
+	if html, err = c.renderHTMLTemplate("digest-html", data); err != nil {
+		return
+	}
+
+	sc := c.smtpSettings(cfg.SMTP)
+	msg := digestMessage(sc.From, sc.To, string(bytes.TrimSpace(subject)), text, html, c.now())
+
+	if c.dryRun {
+		c.response = msg
+		return
+	}
+
+	if sc.Addr == "" || sc.From == "" || len(sc.To) == 0 {
+		return fmt.Errorf("%w: digest requires an SMTP address, sender and recipients "+
+			"(--smtp-addr, --smtp-from, --smtp-to or the smtp section of --config), or --dry-run", errUsage)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err = sendMail(ctx, sc, msg); err != nil {
+		return
+	}
+
+	c.logf(LogVerbose, "digest: sent %d entries to %s", len(entries), strings.Join(sc.To, ", "))
+
+	return
+}
+
+// digestEntries returns the releases of product that are EOL, EOAS or reach
+// EOL within window. Only the listed releases are considered, if any, or
+// else all the ones not EOL yet.
+func digestEntries(wp watchProduct, product map[string]any, now time.Time, window time.Duration) (entries []digestEntry) {
+	releases := toSlice(product["releases"])
+
+	var tracked []map[string]any
+
+	if len(wp.Releases) == 0 {
+		for _, r := range releases {
+			if rel, ok := r.(map[string]any); ok && releaseStatus(rel) != StatusEol {
+				tracked = append(tracked, rel)
+			}
+		}
+	}
+
+	for _, version := range wp.Releases {
+		if rel, _, _ := matchRelease(releases, version); rel != nil {
+			tracked = append(tracked, rel)
+		}
+	}
+
+	for _, rel := range tracked {
+		entry := digestEntry{
+			Product:  wp.Product,
+			Release:  getString(rel, "name"),
+			Label:    getString(rel, "label"),
+			EoasFrom: getString(rel, "eoasFrom"),
+			EolFrom:  getString(rel, "eolFrom"),
+			Link:     releaseLink(rel),
+			DaysLeft: daysUntil(now, getString(rel, "eolFrom")),
+		}
+
+		switch status := releaseStatus(rel); {
+		case status == StatusEol || status == StatusEoas:
+			entry.Reason = status
+		case dateWithin(now, window, entry.EolFrom):
+			entry.Reason = EventEolApproaching
+		default:
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return
+}
+
+// smtpSettings merges the --smtp-* flags and EOL_SMTP_PASSWORD into cfg.
+func (c *client) smtpSettings(cfg smtpConfig) smtpConfig {
+	cfg.Addr = cmp.Or(c.smtpAddr, cfg.Addr)
+	cfg.Username = cmp.Or(c.smtpUser, cfg.Username)
+	cfg.Password = cmp.Or(os.Getenv(smtpPasswordEnv), cfg.Password)
+	cfg.From = cmp.Or(c.smtpFrom, cfg.From)
+
+	if c.smtpTo != "" {
+		cfg.To = strings.Split(c.smtpTo, ",")
+	}
+
+	for i, to := range cfg.To {
+		cfg.To[i] = strings.TrimSpace(to)
+	}
+
+	return cfg
+}
+
+// renderHTMLTemplate is renderTemplate for HTML: the named template (and
+// those it calls) is executed as an html/template, escaping its output.
+func (c *client) renderHTMLTemplate(name string, data any) (out []byte, err error) {
+	set := htmltemplate.New("").Funcs(htmltemplate.FuncMap(funcMap))
+
+	for _, t := range c.templates.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+
+		if _, err = set.AddParseTree(t.Name(), t.Tree.Copy()); err != nil {
+			return
+		}
+	}
+
+	tmpl := set.Lookup(name)
+	if tmpl == nil {
+		return nil, fmt.Errorf("template %s %w", name, errNotFound)
+	}
+
+	v, err := roundTripJSON(data)
+	if err != nil {
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, v)
+
+	return buf.Bytes(), err
+}
+
+// digestMessage builds a multipart/alternative message with the text and
+// HTML bodies. The boundary and Message-ID are derived from the content,
+// so that the same digest always results in the same message.
+func digestMessage(from string, to []string, subject string, text, html []byte, date time.Time) []byte {
+	body := &bytes.Buffer{}
+	mw := multipart.NewWriter(body)
+	sum := sha256.Sum256(append(text, html...))
+
+	//nolint:errcheck,gosec // boundary is valid by construction
+	mw.SetBoundary(fmt.Sprintf("eol-digest-%x", sum)[:43])
+
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{{"text/plain", text}, {"text/html", html}} {
+		pw, _ := mw.CreatePart(textproto.MIMEHeader{ //nolint:errcheck // writes to a buffer
+			"Content-Type":              {part.contentType + "; charset=utf-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		qw := quotedprintable.NewWriter(pw)
+		qw.Write(part.content) //nolint:errcheck,gosec // writes to a buffer
+		qw.Close()             //nolint:errcheck,gosec // writes to a buffer
+	}
+
+	mw.Close() //nolint:errcheck,gosec // writes to a buffer
+
+	msg := &bytes.Buffer{}
+	for _, h := range [][2]string{
+		{"From", from},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", date.Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<eol-digest.%d.%x@%s>", date.Unix(), sum[:8], messageIDDomain(from))},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": mw.Boundary()})},
+	} {
+		fmt.Fprintf(msg, "%s: %s\r\n", h[0], h[1])
+	}
+
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes()
+}
+
+// messageIDDomain returns the domain of the sender address, for the
+// Message-ID, or localhost if it has none.
+func messageIDDomain(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if _, domain, ok := strings.Cut(addr.Address, "@"); ok {
+			return domain
+		}
+	}
+
+	return "localhost"
+}
+
+// sendMail delivers msg, upgrading the connection with STARTTLS. Servers
+// not offering STARTTLS are only accepted on loopback addresses. The whole
+// exchange must complete within smtpTimeout (and before ctx is done), so a
+// server stalling after connecting fails the digest rather than hang it.
+func sendMail(ctx context.Context, cfg smtpConfig, msg []byte) (err error) {
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("%w: invalid SMTP address %s: %w", errUsage, cfg.Addr, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errNetwork, err)
+	}
+
+	deadline, _ := ctx.Deadline()
+	if err = conn.SetDeadline(deadline); err != nil {
+		conn.Close() //nolint:errcheck,gosec // ok
+		return fmt.Errorf("%w: %w", errNetwork, err)
+	}
+
+	// Interrupted (or timed out) mid exchange: unblock the pending I/O.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) }) //nolint:errcheck,gosec // ok
+	defer stop()
+
+	cl, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close() //nolint:errcheck,gosec // ok
+		return fmt.Errorf("%w: %w", errNetwork, err)
+	}
+	defer cl.Close() //nolint:errcheck // ok
+
+	if ok, _ := cl.Extension("STARTTLS"); ok {
+		if err = cl.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return
+		}
+	} else if !isLoopback(host) {
+		return fmt.Errorf("SMTP server %s does not support STARTTLS", cfg.Addr)
+	}
+
+	if cfg.Username != "" {
+		if err = cl.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, host)); err != nil {
+			return
+		}
+	}
+
+	if err = cl.Mail(cfg.From); err != nil {
+		return
+	}
+
+	for _, to := range cfg.To {
+		if err = cl.Rcpt(to); err != nil {
+			return
+		}
+	}
+
+	w, err := cl.Data()
+	if err != nil {
+		return
+	}
+
+	if _, err = w.Write(msg); err != nil {
+		return
+	}
+
+	if err = w.Close(); err != nil {
+		return
+	}
+
+	return cl.Quit()
+}
+
+func isLoopback(host string) bool {
+	ip := net.ParseIP(host)
+	return host == "localhost" || (ip != nil && ip.IsLoopback())
+}
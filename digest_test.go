@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientDigest(t *testing.T) {
+	t.Parallel()
+
+	cfg := filepath.Join("testdata", "watch", "digest.json")
+
+	//nolint:govet // ok
+	cases := []struct {
+		args   []string
+		golden string
+		expErr error
+	}{
+		{[]string{"digest", "--config", cfg, "--as-of", "2025-06-01", "--dry-run"}, "dry-run", nil},
+		{[]string{"digest", "--config", cfg, "--as-of", "2025-06-01", "--dry-run", "--within", "1d"}, "dry-run-eol", nil},
+		{[]string{"digest", "--config", cfg, "--dry-run", "--within", "soon"}, "", errUsage},
+		{[]string{"digest", "--dry-run"}, "", errUsage},
+		{[]string{"digest", "--config", cfg, "--smtp-addr", "smtp.invalid:25"}, "", errNetwork},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.httpClient = buf, &mockHTTPClient{}
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "digest", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected %q, got %q", exp, x)
+			}
+		})
+	}
+}
+
+func TestSendMail(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+
+	go smtpStub(ln, received)
+
+	cfg := smtpConfig{Addr: ln.Addr().String(), From: "eol@example.com", To: []string{"a@example.com", "b@example.com"}}
+	msg := digestMessage(cfg.From, cfg.To, "eol digest", []byte("text"), []byte("<p>html</p>"), time.Now())
+
+	if err = sendMail(context.Background(), cfg, msg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got := <-received
+	for _, exp := range []string{
+		"MAIL FROM:<eol@example.com>", "RCPT TO:<a@example.com>", "RCPT TO:<b@example.com>",
+		"Subject: eol digest", "Content-Type: text/html; charset=utf-8", "<p>html</p>",
+	} {
+		if !strings.Contains(got, exp) {
+			t.Fatalf("Expected the session to contain %q, got %q", exp, got)
+		}
+	}
+}
+
+func TestSendMailStalled(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer ln.Close()
+
+	// Accept, then never greet, until the client hangs up.
+	go func() {
+		if conn, err := ln.Accept(); err == nil {
+			io.Copy(io.Discard, conn) //nolint:errcheck // ok
+			conn.Close()              //nolint:errcheck,gosec // ok
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	cfg := smtpConfig{Addr: ln.Addr().String(), From: "eol@example.com", To: []string{"a@example.com"}}
+
+	if err = sendMail(ctx, cfg, []byte("msg")); err == nil {
+		t.Fatalf("Expected an error from a stalled server")
+	} else if d := time.Since(start); d > 2*time.Second {
+		t.Fatalf("Expected sendMail to give up at the deadline, took %v", d)
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	t.Parallel()
+
+	for host, exp := range map[string]bool{
+		"localhost": true, "127.0.0.1": true, "::1": true, "smtp.example.com": false, "10.0.0.1": false,
+	} {
+		if got := isLoopback(host); got != exp {
+			t.Fatalf("Expected isLoopback(%q) to be %v, got %v", host, exp, got)
+		}
+	}
+}
+
+// smtpStub accepts a single SMTP session, without STARTTLS or AUTH,
+// and sends the whole conversation, as seen by the server, to received.
+func smtpStub(ln net.Listener, received chan<- string) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := &strings.Builder{}
+	r := bufio.NewReader(conn)
+	reply := func(s string) { fmt.Fprintf(conn, "%s\r\n", s) }
+	inData := false
+
+	reply("220 localhost ESMTP stub")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+
+		session.WriteString(line)
+
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case inData && cmd == ".":
+			inData = false
+
+			reply("250 queued")
+		case inData:
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case cmd == "DATA":
+			inData = true
+
+			reply("354 go ahead")
+		case cmd == "QUIT":
+			reply("221 bye")
+			received <- session.String()
+
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+
+	received <- session.String()
+}
+
+func TestRenderHTMLTemplate(t *testing.T) {
+	t.Parallel()
+
+	c, err := newClient([]string{"digest"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	entry := digestEntry{Product: "<script>", Release: "1", Reason: "eol", Link: "javascript:alert(1)"}
+
+	out, err := c.renderHTMLTemplate("digest-html", map[string]any{"total": 1, "entries": []digestEntry{entry}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if bytes.Contains(out, []byte("<script>")) || bytes.Contains(out, []byte("javascript:")) {
+		t.Fatalf("Expected the entry escaped, got %s", out)
+	}
+}
@@ -25,16 +25,52 @@ import (
 )
 
 type client struct {
-	sink           io.Writer
-	response       []byte
-	baseURL        *url.URL
-	httpClient     //nolint:embeddedstructfieldcheck // nope
-	templates      *template.Template
-	command        string
-	templatesDir   string
-	inlineTemplate string
-	args           []string
-	format         outputFormat
+	sink               io.Writer
+	errSink            io.Writer
+	stdin              io.Reader // Read by commands given "-" as path.
+	deferredErr        error     // Returned by handle() after the response was rendered.
+	response           []byte
+	baseURL            *url.URL
+	httpClient                       //nolint:embeddedstructfieldcheck // nope
+	runner             commandRunner // Runs the version commands of host.
+	templates          *template.Template
+	command            string
+	templatesDir       string
+	inlineTemplate     string
+	args               []string
+	product            string // The product args[0] resolved to, for the templates' arg1.
+	asOfTime           time.Time
+	asOf               string
+	since              string
+	config             string
+	interval           string
+	stateFile          string
+	smtpAddr           string
+	smtpUser           string
+	smtpFrom           string
+	smtpTo             string
+	inventoryFile      string
+	githubOutput       string // $GITHUB_OUTPUT, written by -f github.
+	githubSummary      string // $GITHUB_STEP_SUMMARY, appended to by -f github.
+	owner              string
+	env                string
+	notes              string
+	groupBy            string
+	kubeVersion        string
+	timeout            string
+	listen             string
+	cacheTTL           string
+	apply              string
+	within             string
+	from               string
+	to                 string
+	category           string
+	tag                string
+	format             outputFormat
+	logLevel           logLevel
+	requireLatestPatch bool
+	once               bool
+	dryRun             bool
 }
 
 type httpClient interface {
@@ -43,6 +79,16 @@ type httpClient interface {
 
 type outputFormat int
 
+type logLevel int
+
+// Log levels, for diagnostics written to stderr.
+const (
+	LogQuiet   logLevel = iota - 1 // Errors only, no usage text or warnings.
+	LogNormal                      // Errors and warnings.
+	LogVerbose                     // Also informational messages.
+	LogDebug                       // Also every request URL, status and timing.
+)
+
 // Default values.
 const (
 	DefaultTimeout = 30 * time.Second
@@ -53,6 +99,9 @@ const (
 const (
 	FormatText outputFormat = iota
 	FormatJSON
+	FormatMarkdown
+	FormatGitHub
+	FormatGitLabCodeQuality
 )
 
 //nolint:gochecknoglobals // ok
@@ -62,8 +111,40 @@ var (
 		"exit": func(code int) string { os.Exit(code); return "" },
 		"add":  func(a, b int) int { return a + b }, "mul": func(a, b int) int { return a * b },
 		"collect": collect, "toStringSlice": toStringSlice,
+		"ghProperty": githubProperty, "ghData": githubData,
 	}
-	rawOutput   = []string{"help", "version", "completion", "completion-bash", "completion-zsh", "templates-export"}
+	// commandFlags scopes the command specific flags: the other commands
+	// reject them (with errUsage) rather than silently ignore them. --within
+	// is shared by upcoming, watch, digest and the github and
+	// gitlab-codequality formats, so it is not scoped.
+	commandFlags = map[string][]string{
+		"--since":                {"diff"},
+		"--config":               {"watch", "digest"},
+		"--interval":             {"watch"},
+		"--state":                {"watch"},
+		"--once":                 {"watch"},
+		"--smtp-addr":            {"digest"},
+		"--smtp-user":            {"digest"},
+		"--smtp-from":            {"digest"},
+		"--smtp-to":              {"digest"},
+		"--dry-run":              {"digest", "suggest"},
+		"--inventory":            {"inventory"},
+		"--owner":                {"inventory"},
+		"--env":                  {"inventory"},
+		"--notes":                {"inventory"},
+		"--group-by":             {"inventory"},
+		"--kube-version":         {"scan"},
+		"--timeout":              {"host"},
+		"--listen":               {"serve-api"},
+		"--cache-ttl":            {"serve-api"},
+		"--apply":                {"suggest"},
+		"--require-latest-patch": {"release", "release-badge", "os"},
+		"--from":                 {"upcoming"},
+		"--to":                   {"upcoming"},
+		"--category":             {"upcoming"},
+		"--tag":                  {"upcoming"},
+	}
+	rawOutput   = []string{"help", "version", "completion", "completion-bash", "completion-zsh", "templates-export", "digest"}
 	reCustomDur = regexp.MustCompile(`^(\d+)(d|wk|mo)$`)
 	userAgent   = "eol-go-client"
 	version     = "unk"
@@ -82,6 +163,7 @@ var (
 	errInlineTemplate  = errors.New("inline template seems wrong, did you indend to use -f json?")
 	errInvalidDuration = errors.New("invalid duration")
 	errInvalidDict     = errors.New("invalid dict")
+	errInvalidImage    = errors.New("not a docker save or OCI layout tarball")
 )
 
 //go:embed completions/bash.sh
@@ -98,6 +180,9 @@ func newClient(args []string) (c *client, err error) {
 
 	c = &client{
 		sink:    os.Stdout,
+		errSink: os.Stderr,
+		stdin:   os.Stdin,
+		runner:  execRunner{},
 		baseURL: baseURL,
 		format:  FormatText,
 	}
@@ -106,12 +191,25 @@ func newClient(args []string) (c *client, err error) {
 		return
 	}
 
+	if c.asOf != "" {
+		if c.asOfTime, err = time.Parse(time.DateOnly, c.asOf); err != nil {
+			return c, fmt.Errorf("%w: invalid --as-of date: %w", errUsage, err)
+		}
+	}
+
+	if c.format == FormatGitHub {
+		c.githubOutput, c.githubSummary = os.Getenv("GITHUB_OUTPUT"), os.Getenv("GITHUB_STEP_SUMMARY")
+	}
+
 	if c.httpClient == nil {
 		c.httpClient = &http.Client{Timeout: DefaultTimeout}
 	}
 
 	if c.templates == nil { //nolint:nestif // ok
-		c.templates = template.New("master").Funcs(funcMap)
+		c.templates = template.New("master").Funcs(funcMap).Funcs(template.FuncMap{
+			"eolWithin": func(duration string, eolDate any) bool { return eolWithinAt(c.now(), duration, eolDate) },
+			"ghLevel":   c.githubLevel,
+		})
 
 		if err = loadTemplates(c.templates, templates.Templates); err != nil {
 			return
@@ -133,7 +231,7 @@ func newClient(args []string) (c *client, err error) {
 
 //nolint:gocyclo,cyclop,funlen // ok
 func (c *client) handle() (err error) {
-	c.response = nil
+	c.response, c.deferredErr = nil, nil
 	cmd := c.command
 
 	switch cmd {
@@ -148,26 +246,49 @@ func (c *client) handle() (err error) {
 	case "products-full":
 		err = c.doRequest("/products/full")
 	case "product":
-		err = c.doRequest("/products/" + c.args[0])
+		err = c.withProduct(c.args[0], func(pn string) error {
+			c.product = pn
+			return c.doRequest("/products/" + pn)
+		})
 	case "release", "release-badge":
-		pn, rel := c.args[0], c.args[1]
-		versions, found := generateVersionVariants(rel), false
-
-		for _, version := range versions {
-			err = c.doRequest("/products/" + pn + "/releases/" + version)
-			if err == nil {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			err = fmt.Errorf("%w %s with any of the attempted versions: %v",
-				errReleaseNotFound, pn, versions)
-		}
+		err = c.withProduct(c.args[0], func(pn string) error {
+			c.product = pn
+			return c.release(pn, c.args[1])
+		})
 	case "latest":
 		c.command = "release"
-		err = c.doRequest("/products/" + c.args[0] + "/releases/latest")
+		err = c.withProduct(c.args[0], func(pn string) error {
+			c.product = pn
+			return c.doRequest("/products/" + pn + "/releases/latest")
+		})
+	case "search":
+		err = c.search(c.args[0])
+	case "upcoming":
+		err = c.upcoming()
+	case "compare":
+		err = c.compare()
+	case "diff":
+		err = c.diff()
+	case "watch":
+		err = c.watch()
+	case "digest":
+		err = c.digest()
+	case "inventory":
+		err = c.inventory()
+	case "scan":
+		err = c.scan()
+	case "host":
+		err = c.host()
+	case "os":
+		err = c.osRelease()
+	case "hook":
+		err = c.hook()
+	case "suggest":
+		err = c.withProduct(c.args[0], func(pn string) error { return c.suggest(pn, c.args[1]) })
+	case "serve-api":
+		err = c.serveAPI()
+	case "mcp":
+		err = c.mcp()
 	case "categories":
 		err = c.doRequest("/categories")
 	case "category":
@@ -194,13 +315,20 @@ func (c *client) handle() (err error) {
 		return
 	}
 
-	if c.format == FormatJSON || slices.Contains(rawOutput, c.command) {
+	switch {
+	case c.format == FormatJSON || slices.Contains(rawOutput, c.command):
 		_, err = c.sink.Write(c.response)
-	} else {
+	case c.format == FormatGitLabCodeQuality:
+		err = c.gitlabCodeQuality()
+	default:
 		err = c.executeTemplate(c.command)
 	}
 
-	return
+	if err == nil && c.format == FormatGitHub {
+		err = c.githubStep()
+	}
+
+	return cmp.Or(err, c.deferredErr)
 }
 
 func (c *client) printHelp() {
@@ -226,6 +354,8 @@ func (c *client) parseFlags(args []string) (err error) {
 		return fmt.Errorf("%w: requires a command", errUsage)
 	}
 
+	var scoped []string // The command specific flags given.
+
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 		switch arg {
@@ -242,6 +372,12 @@ func (c *client) parseFlags(args []string) (err error) {
 				c.format = FormatJSON
 			case "text":
 				c.format = FormatText
+			case "markdown", "md":
+				c.format = FormatMarkdown
+			case "github":
+				c.format = FormatGitHub
+			case "gitlab-codequality":
+				c.format = FormatGitLabCodeQuality
 			default:
 				return fmt.Errorf("%w '%s'", errUnsupportedFormat, format)
 			}
@@ -263,9 +399,36 @@ func (c *client) parseFlags(args []string) (err error) {
 			}
 
 			i++ // Skip the template argument.
+		case "--require-latest-patch":
+			c.requireLatestPatch, scoped = true, append(scoped, arg)
+		case "--once":
+			c.once, scoped = true, append(scoped, arg)
+		case "--dry-run":
+			c.dryRun, scoped = true, append(scoped, arg)
+		case "-q", "--quiet":
+			c.logLevel = LogQuiet
+		case "-v", "--verbose":
+			c.logLevel = LogVerbose
+		case "--debug":
+			c.logLevel = LogDebug
 		case "-h", "--help", "help":
 			c.command = "help"
 		default:
+			if p, ok := c.stringFlags()[arg]; ok {
+				if i+1 >= len(args) {
+					return fmt.Errorf("%w: %s requires a value", errUsage, arg)
+				}
+
+				i++
+				*p = args[i]
+
+				if commandFlags[arg] != nil {
+					scoped = append(scoped, arg)
+				}
+
+				continue
+			}
+
 			if c.command == "" && !strings.HasPrefix(arg, "-") {
 				c.command = arg
 			} else {
@@ -278,6 +441,10 @@ func (c *client) parseFlags(args []string) (err error) {
 		return fmt.Errorf("%w: requires a command", errUsage)
 	}
 
+	if err = c.checkFlags(scoped); err != nil {
+		return
+	}
+
 	switch c.command {
 	case "completion":
 		if shell := os.Getenv("SHELL"); strings.Contains(shell, "zsh") {
@@ -285,11 +452,11 @@ func (c *client) parseFlags(args []string) (err error) {
 		} else {
 			c.command = "completion-bash"
 		}
-	case "product", "category", "tag", "identifier", "latest":
+	case "product", "category", "tag", "identifier", "latest", "search", "inventory", "scan":
 		if len(c.args) < 1 || c.args[0] == "" {
 			return fmt.Errorf("%w: %s command requires an argument", errUsage, c.command)
 		}
-	case "release", "release-badge":
+	case "release", "release-badge", "suggest":
 		if len(c.args) < 2 || c.args[0] == "" || c.args[1] == "" {
 			return fmt.Errorf("%w: %s command requires two arguments", errUsage, c.command)
 		}
@@ -298,13 +465,75 @@ func (c *client) parseFlags(args []string) (err error) {
 	return
 }
 
+// checkFlags fails if any of the command specific flags given does not
+// apply to the command.
+func (c *client) checkFlags(scoped []string) error {
+	for _, flag := range scoped {
+		if slices.Contains(commandFlags[flag], c.command) || c.command == "help" {
+			continue
+		}
+
+		return fmt.Errorf("%w: %s does not apply to %s", errUsage, flag, c.command)
+	}
+
+	return nil
+}
+
+// stringFlags maps the flags that take a plain string value to their field.
+func (c *client) stringFlags() map[string]*string {
+	return map[string]*string{
+		"--as-of":        &c.asOf,
+		"--since":        &c.since,
+		"--config":       &c.config,
+		"--interval":     &c.interval,
+		"--state":        &c.stateFile,
+		"--smtp-addr":    &c.smtpAddr,
+		"--smtp-user":    &c.smtpUser,
+		"--smtp-from":    &c.smtpFrom,
+		"--smtp-to":      &c.smtpTo,
+		"--inventory":    &c.inventoryFile,
+		"--owner":        &c.owner,
+		"--env":          &c.env,
+		"--notes":        &c.notes,
+		"--group-by":     &c.groupBy,
+		"--kube-version": &c.kubeVersion,
+		"--timeout":      &c.timeout,
+		"--listen":       &c.listen,
+		"--cache-ttl":    &c.cacheTTL,
+		"--apply":        &c.apply,
+		"--within":       &c.within,
+		"--from":         &c.from,
+		"--to":           &c.to,
+		"--category":     &c.category,
+		"--tag":          &c.tag,
+	}
+}
+
 // Executes a template using the prepared templates.
 // Inline template is executed via "_inline" name.
 func (c *client) executeTemplate(name string) (err error) {
-	if c.inlineTemplate != "" {
+	var format string
+
+	switch {
+	case c.inlineTemplate != "":
 		name = "_inline"
+	case c.format == FormatMarkdown:
+		format = "markdown"
+	case c.format == FormatGitHub:
+		format = "github"
 	}
 
+	if format != "" {
+		if name += "-" + format; c.templates.Lookup(name) == nil {
+			return fmt.Errorf("%w '%s' for %s", errUnsupportedFormat, format, c.command)
+		}
+	}
+
+	return c.executeTemplateTo(c.sink, name)
+}
+
+// executeTemplateTo executes the named template on the response's result, to w.
+func (c *client) executeTemplateTo(w io.Writer, name string) (err error) {
 	tmpl := c.templates.Lookup(name)
 	if tmpl == nil {
 		return fmt.Errorf("template %s %w", name, errNotFound)
@@ -318,15 +547,19 @@ func (c *client) executeTemplate(name string) (err error) {
 	//nolint:wrapcheck // ok
 	switch v := x["result"].(type) {
 	case []any:
-		return tmpl.Execute(c.sink, v)
+		return tmpl.Execute(w, v)
 	case map[string]any:
 		for i, x := range c.args {
 			v[fmt.Sprintf("arg%d", i+1)] = x
 		}
 
-		return tmpl.Execute(c.sink, v)
+		if c.product != "" {
+			v["arg1"] = c.product
+		}
+
+		return tmpl.Execute(w, v)
 	default:
-		return tmpl.Execute(c.sink, v)
+		return tmpl.Execute(w, v)
 	}
 }
 
@@ -410,6 +643,12 @@ func (c *client) templatesExport(dir string) (err error) {
 }
 
 func (c *client) doRequest(endpoint string) (err error) {
+	c.response, err = c.fetchAsOf(endpoint)
+	return
+}
+
+// fetch retrieves endpoint from the API and returns the raw response body.
+func (c *client) fetch(endpoint string) (body []byte, err error) {
 	urL := buildURL(*c.baseURL, endpoint)
 
 	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, urL, http.NoBody)
@@ -420,23 +659,33 @@ func (c *client) doRequest(endpoint string) (err error) {
 	req.Header.Set("User-Agent", userAgent+"/"+version)
 	req.Header.Set("Accept", "application/json")
 
+	start := time.Now()
+
 	resp, err := c.Do(req)
 	if err != nil {
-		return
+		c.logf(LogDebug, "GET %s failed after %v: %v", urL, time.Since(start), err)
+		return nil, fmt.Errorf("%w: %w", errNetwork, err)
 	}
 	defer resp.Body.Close() //nolint:errcheck // ok
 
+	c.logf(LogDebug, "GET %s -> %d %s (%v)", urL, resp.StatusCode, http.StatusText(resp.StatusCode), time.Since(start))
+
 	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == http.StatusNotFound {
-			return errNotFound
-		}
+		body, _ = io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize)) //nolint:errcheck // best effort
 
-		return fmt.Errorf("%s (%d)", http.StatusText(resp.StatusCode), resp.StatusCode) //nolint:err113 // ok
+		return nil, &APIError{URL: urL, StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	c.response, err = io.ReadAll(resp.Body)
+	return io.ReadAll(resp.Body)
+}
 
-	return
+// logf writes a diagnostic line to errSink, if level is enabled.
+func (c *client) logf(level logLevel, format string, args ...any) {
+	if c.errSink == nil || c.logLevel < level {
+		return
+	}
+
+	fmt.Fprintf(c.errSink, format+"\n", args...) //nolint:errcheck // best effort
 }
 
 //nolint:gochecknoinits // ok
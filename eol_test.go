@@ -79,8 +79,34 @@ func TestClientHandle(t *testing.T) {
 		{"release go 1.24.6.100", nil},
 		{"release go 1.24.6", nil},
 		{"release go 1.24", nil},
+		{"release go go1.24rc1", nil},
+		{"release go 1.24.3", nil},
+		{"release go 1.24.3 --require-latest-patch", errOutdated},
+		{"release go 1.24.6 --require-latest-patch", nil},
+		{"release ubuntu 22.04.3-lts", nil},
 		{"release go 1", errReleaseNotFound},
+		{"release ubuntu 24.04 --as-of 2029-06-01", nil},
+		{"index --as-of 2025-13-01", errUsage},
+		{"compare ubuntu 22.04 24.04 --as-of 2025-08-27", nil},
+		{"compare ubuntu 22.04 24.04 --as-of 2025-08-27 -f json", nil},
+		{"compare golang 1.24 1.23 --as-of 2025-08-27", nil},
+		{"compare go 1.24 1.22 --as-of 2024-06-01", nil},
+		{"compare go 1.24 1.99", errReleaseNotFound},
+		{"compare ubuntu 22.04", errUsage},
 		{"latest ubuntu", nil},
+		{"latest golang", nil},
+		{"product golang", nil},
+		{"product golng", errNotFound},
+		{"release golang 1.24", nil},
+		{"search golang", nil},
+		{"search kube", nil},
+		{"upcoming --from 2025-09-01 --to 2025-12-31 --category os", nil},
+		{"upcoming golang ubuntu --from 2025-01-01 --within 2mo", nil},
+		{"upcoming --tag lang --from 2025-09-01 --to 2025-10-31 -f json", nil},
+		{"upcoming --from 2025-13-01", errUsage},
+		{"upcoming --within 1y", errUsage},
+		{"upcoming --from 2025-09-01 --to 2025-08-31", errUsage},
+		{"upcoming --within -24h", errUsage},
 		{"categories", nil},
 		{"category os", nil},
 		{"tags", nil},
@@ -115,6 +141,9 @@ func TestClientHandle(t *testing.T) {
 				t.Fatalf("Expected error %v, got %v", tc.expError, err)
 			} else if err == nil {
 				args := append([]string{c.command}, c.args...)
+				if c.product != "" {
+					args[1] = c.product // Aliases share the golden copy of their product.
+				}
 				if c.format != FormatText {
 					args = append(args, "json")
 				}
@@ -180,6 +209,7 @@ func TestClientParseFlags(t *testing.T) {
 		{[]string{"tag"}, &client{command: "tag"}, errUsage},
 		{[]string{"identifier"}, &client{command: "identifier"}, errUsage},
 		{[]string{"latest"}, &client{command: "latest"}, errUsage},
+		{[]string{"search"}, &client{command: "search"}, errUsage},
 		{[]string{"completion"}, &client{command: "completion-bash"}, nil},
 		{[]string{"categories"}, &client{command: "categories"}, nil},
 		{[]string{"tags"}, &client{command: "tags"}, nil},
@@ -188,6 +218,34 @@ func TestClientParseFlags(t *testing.T) {
 		{[]string{"index", "--templates-dir"}, &client{command: "index"}, errUsage},
 		{[]string{"release", "go"}, &client{command: "release", args: []string{"go"}}, errUsage},
 		{[]string{"release", "go", "1.24"}, &client{command: "release", args: []string{"go", "1.24"}}, nil},
+		{[]string{"index", "--require-latest-patch"}, nil, errUsage},
+		{
+			[]string{"release", "go", "1.24", "--require-latest-patch"},
+			&client{command: "release", args: []string{"go", "1.24"}, requireLatestPatch: true}, nil,
+		},
+		{[]string{"release", "go", "1.24", "--tag", "lang"}, nil, errUsage},
+		{[]string{"index", "--since", "snapshot.json"}, nil, errUsage},
+		{[]string{"index", "--once"}, nil, errUsage},
+		{[]string{"watch", "--smtp-addr", "localhost:25"}, nil, errUsage},
+		{[]string{"scan", "dir", "--inventory", "eol-inventory.json"}, nil, errUsage},
+		{[]string{"index", "--kube-version", "1.30"}, nil, errUsage},
+		{[]string{"scan", "dir", "--timeout", "1s"}, nil, errUsage},
+		{[]string{"release", "go", "1.24", "--listen", ":80"}, nil, errUsage},
+		{[]string{"scan", "dir", "--apply", "go.mod"}, nil, errUsage},
+		{[]string{"upcoming", "--within"}, nil, errUsage},
+		{[]string{"index", "--as-of", "2027-03-01"}, &client{command: "index", asOf: "2027-03-01"}, nil},
+		{[]string{"upcoming", "--within", "6mo", "--tag", "lang"}, &client{command: "upcoming", within: "6mo", tag: "lang"}, nil},
+		{
+			[]string{"watch", "--config", "w.json", "--interval", "1h", "--state", "s.json", "--once"},
+			&client{command: "watch", config: "w.json", interval: "1h", stateFile: "s.json", once: true}, nil,
+		},
+		{
+			[]string{"digest", "--smtp-addr", "localhost:25", "--smtp-to", "a@x,b@x", "--dry-run"},
+			&client{command: "digest", smtpAddr: "localhost:25", smtpTo: "a@x,b@x", dryRun: true}, nil,
+		},
+		{[]string{"index", "-q"}, &client{command: "index", logLevel: LogQuiet}, nil},
+		{[]string{"index", "--verbose"}, &client{command: "index", logLevel: LogVerbose}, nil},
+		{[]string{"--debug", "index"}, &client{command: "index", logLevel: LogDebug}, nil},
 	}
 
 	for _, tc := range cases {
@@ -204,6 +262,52 @@ func TestClientParseFlags(t *testing.T) {
 	}
 }
 
+func TestClientLogf(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		level logLevel
+		exp   []string
+	}{
+		{LogQuiet, nil},
+		{LogNormal, nil},
+		{LogVerbose, []string{`release go: picked "1.24" (no release "1.24.6.100", fell back to "1.24")`}},
+		{LogDebug, []string{
+			`release go: picked "1.24"`,
+			"GET https://endoflife.date/api/v1/products/go -> 200 OK",
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(fmt.Sprint(tc.level), func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient([]string{"release", "go", "1.24.6.100"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			errBuf := &bytes.Buffer{}
+			c.sink, c.errSink, c.logLevel = io.Discard, errBuf, tc.level
+			c.httpClient = &mockHTTPClient{}
+
+			if err = c.handle(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(tc.exp) == 0 && errBuf.Len() > 0 {
+				t.Fatalf("Expected no diagnostics, got %q", errBuf.String())
+			}
+
+			for _, exp := range tc.exp {
+				if !strings.Contains(errBuf.String(), exp) {
+					t.Fatalf("Expected diagnostics to contain %q, got %q", exp, errBuf.String())
+				}
+			}
+		})
+	}
+}
+
 func TestClientExecuteTemplate(t *testing.T) {
 	t.Parallel()
 	t.Skip("Tested indirectly in TestClientHandle")
@@ -232,7 +336,6 @@ func (m *mockHTTPClient) Do(r *http.Request) (w *http.Response, err error) {
 		code := http.StatusOK
 		if bytes.Contains(content, []byte("Page not Found")) {
 			code = http.StatusNotFound
-			err = errNotFound
 		}
 
 		return &http.Response{
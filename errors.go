@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when the API responds with anything other than 200 OK.
+type APIError struct {
+	URL        string
+	Body       string
+	StatusCode int
+}
+
+// ReleaseNotFoundError is returned when none of the attempted version
+// variants match a release of the given product.
+type ReleaseNotFoundError struct {
+	Product  string
+	Variants []string
+}
+
+// ProductNotFoundError is returned when a product name is neither a product
+// nor an alias of one. It carries the closest matching product names.
+type ProductNotFoundError struct {
+	Product     string
+	Suggestions []string
+}
+
+// OutdatedError is returned by --require-latest-patch when the given version
+// is behind the latest patch of its release.
+type OutdatedError struct {
+	Product string
+	Version string
+	Latest  string
+	Link    string
+	Behind  int
+}
+
+// Exit codes, one per failure class.
+const (
+	ExitOK       = 0
+	ExitUsage    = 1
+	ExitFailure  = 2
+	ExitNotFound = 3
+	ExitNetwork  = 4
+	ExitAPI      = 5
+	ExitOutdated = 6
+)
+
+// Maximum number of response body bytes kept in an [APIError].
+const maxErrorBodySize = 512
+
+var (
+	errNetwork  = errors.New("network error")
+	errOutdated = errors.New("not the latest patch")
+
+	errInventoryCheck = errors.New("inventory check failed")
+	errEolFound       = errors.New("EOL releases found")
+)
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (%d) for %s", http.StatusText(e.StatusCode), e.StatusCode, e.URL)
+}
+
+// Is lets a 404 [APIError] match errNotFound.
+func (e *APIError) Is(target error) bool {
+	return target == errNotFound && e.StatusCode == http.StatusNotFound
+}
+
+func (e *ReleaseNotFoundError) Error() string {
+	return fmt.Sprintf("%v %s with any of the attempted versions: %v",
+		errReleaseNotFound, e.Product, e.Variants)
+}
+
+func (e *ReleaseNotFoundError) Unwrap() error { return errReleaseNotFound }
+
+func (e *ProductNotFoundError) Error() string {
+	msg := fmt.Sprintf("product %s %v", e.Product, errNotFound)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(", did you mean %s?", strings.Join(e.Suggestions, ", "))
+	}
+
+	return msg
+}
+
+func (e *ProductNotFoundError) Unwrap() error { return errNotFound }
+
+func (e *OutdatedError) Error() string {
+	msg := fmt.Sprintf("%s %s is %d behind the latest patch %s", e.Product, e.Version, e.Behind, e.Latest)
+	if e.Link != "" {
+		msg += ", see " + e.Link
+	}
+
+	return msg
+}
+
+func (e *OutdatedError) Unwrap() error { return errOutdated }
+
+// exitCode maps an error to the exit code of its failure class.
+func exitCode(err error) int {
+	var apiErr *APIError
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, errUsage):
+		return ExitUsage
+	case errors.Is(err, errReleaseNotFound), errors.Is(err, errNotFound):
+		return ExitNotFound
+	case errors.Is(err, errNetwork):
+		return ExitNetwork
+	case errors.Is(err, errOutdated):
+		return ExitOutdated
+	case errors.As(err, &apiErr):
+		return ExitAPI
+	default:
+		return ExitFailure
+	}
+}
+
+// errorJSON renders err as a JSON error object, suitable for automation.
+func errorJSON(err error) []byte {
+	type errorObject struct {
+		Type        string   `json:"type"`
+		Message     string   `json:"message"`
+		URL         string   `json:"url,omitempty"`
+		Body        string   `json:"body,omitempty"`
+		Product     string   `json:"product,omitempty"`
+		Variants    []string `json:"variants,omitempty"`
+		Suggestions []string `json:"suggestions,omitempty"`
+		StatusCode  int      `json:"statusCode,omitempty"`
+		ExitCode    int      `json:"exitCode"`
+	}
+
+	var (
+		apiErr  *APIError
+		relErr  *ReleaseNotFoundError
+		prodErr *ProductNotFoundError
+	)
+
+	obj := errorObject{Type: "error", Message: err.Error(), ExitCode: exitCode(err)}
+
+	switch {
+	case errors.As(err, &prodErr):
+		obj.Type, obj.Product, obj.Suggestions = "product_not_found", prodErr.Product, prodErr.Suggestions
+	case errors.As(err, &relErr):
+		obj.Type, obj.Product, obj.Variants = "release_not_found", relErr.Product, relErr.Variants
+	case errors.As(err, &apiErr):
+		obj.Type, obj.URL, obj.Body, obj.StatusCode = "api", apiErr.URL, apiErr.Body, apiErr.StatusCode
+		if apiErr.StatusCode == http.StatusNotFound {
+			obj.Type = "not_found"
+		}
+	case errors.Is(err, errUsage):
+		obj.Type = "usage"
+		obj.Message, _ = strings.CutPrefix(obj.Message, "usage error: ")
+	case errors.Is(err, errNetwork):
+		obj.Type = "network"
+	case errors.Is(err, errOutdated):
+		obj.Type = "outdated"
+	}
+
+	b, _ := json.Marshal(map[string]any{"error": obj}) //nolint:errcheck,errchkjson // plain strings and ints
+
+	return append(b, '\n')
+}
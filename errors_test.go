@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err error
+		exp int
+	}{
+		{nil, ExitOK},
+		{errUnknownCommand, ExitUsage},
+		{&ReleaseNotFoundError{Product: "go", Variants: []string{"1"}}, ExitNotFound},
+		{&APIError{StatusCode: http.StatusNotFound}, ExitNotFound},
+		{&APIError{StatusCode: http.StatusInternalServerError}, ExitAPI},
+		{fmt.Errorf("wrapped: %w", &APIError{StatusCode: http.StatusBadGateway}), ExitAPI},
+		{fmt.Errorf("%w: connection refused", errNetwork), ExitNetwork},
+		{&OutdatedError{Product: "go", Version: "1.24.3", Latest: "1.24.6", Behind: 3}, ExitOutdated},
+		{errInvalidDuration, ExitFailure},
+	}
+
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%v", tc.err), func(t *testing.T) {
+			t.Parallel()
+
+			if got := exitCode(tc.err); got != tc.exp {
+				t.Fatalf("expected exit code %d, got %d", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestReleaseNotFoundError(t *testing.T) {
+	t.Parallel()
+
+	err := error(&ReleaseNotFoundError{Product: "go", Variants: []string{"1.99", "1"}})
+	if !errors.Is(err, errReleaseNotFound) {
+		t.Fatalf("expected %v to match errReleaseNotFound", err)
+	}
+
+	exp := "failed to find release for product go with any of the attempted versions: [1.99 1]"
+	if x := err.Error(); x != exp {
+		t.Fatalf("expected %q, got %q", exp, x)
+	}
+}
+
+func TestErrorJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err            error
+		expType        string
+		expCode        int
+		expStatusCode  int
+		expVariantsLen int
+	}{
+		{&APIError{URL: "https://x/y", StatusCode: http.StatusNotFound}, "not_found", ExitNotFound, 404, 0},
+		{&APIError{URL: "https://x/y", StatusCode: http.StatusInternalServerError}, "api", ExitAPI, 500, 0},
+		{&ReleaseNotFoundError{Product: "go", Variants: []string{"1.99", "1"}}, "release_not_found", ExitNotFound, 0, 2},
+		{fmt.Errorf("%w: boom", errNetwork), "network", ExitNetwork, 0, 0},
+		{errUnknownCommand, "usage", ExitUsage, 0, 0},
+		{errInvalidDict, "error", ExitFailure, 0, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expType, func(t *testing.T) {
+			t.Parallel()
+
+			var got struct {
+				Error struct {
+					Type       string   `json:"type"`
+					Variants   []string `json:"variants"`
+					StatusCode int      `json:"statusCode"`
+					ExitCode   int      `json:"exitCode"`
+				} `json:"error"`
+			}
+
+			if err := json.Unmarshal(errorJSON(tc.err), &got); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if e := got.Error; e.Type != tc.expType || e.ExitCode != tc.expCode ||
+				e.StatusCode != tc.expStatusCode || len(e.Variants) != tc.expVariantsLen {
+				t.Fatalf("Unexpected error object: %+v", e)
+			}
+		})
+	}
+}
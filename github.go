@@ -0,0 +1,135 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// GitHub Actions annotation levels, by urgency.
+const (
+	GitHubError   = "error"
+	GitHubWarning = "warning"
+	GitHubNotice  = "notice"
+)
+
+// githubLevel returns the annotation level of a release (or evaluated
+// finding): error if EOL, warning if EOAS, unmaintained, unknown or reaching
+// EOL within --within (default: 90d), notice otherwise.
+func (c *client) githubLevel(item map[string]any) string {
+	status := getString(item, "status")
+	if status == "" {
+		status = releaseStatus(item)
+	}
+
+	switch {
+	case status == StatusEol:
+		return GitHubError
+	case status != StatusMaintained:
+		return GitHubWarning
+	case c.approachingEol(item["eolFrom"]):
+		return GitHubWarning
+	default:
+		return GitHubNotice
+	}
+}
+
+// approachingEol reports whether eolFrom falls within --within (default:
+// 90d), for the CI formats to flag the releases to upgrade soon.
+func (c *client) approachingEol(eolFrom any) bool {
+	return eolWithinAt(c.now(), cmp.Or(c.within, defaultWithin), eolFrom)
+}
+
+// githubStep does the -f github extras, beyond the annotations: it appends
+// the command's Markdown summary to $GITHUB_STEP_SUMMARY and sets the step
+// outputs in $GITHUB_OUTPUT. Either is skipped when not running in Actions.
+func (c *client) githubStep() (err error) {
+	if c.githubSummary != "" {
+		if err = appendFile(c.githubSummary, func(f *os.File) error {
+			return c.executeTemplateTo(f, c.command+"-github-summary")
+		}); err != nil {
+			return
+		}
+	}
+
+	if c.githubOutput == "" {
+		return
+	}
+
+	outputs, err := githubOutputs(c.response)
+	if err != nil {
+		return
+	}
+
+	return appendFile(c.githubOutput, func(f *os.File) (err error) {
+		for _, k := range []string{"is_eol", "eol_date", "latest"} {
+			if _, err = fmt.Fprintf(f, "%s=%s\n", k, outputs[k]); err != nil {
+				return
+			}
+		}
+
+		return
+	})
+}
+
+// githubOutputs returns the step outputs for the response: is_eol, eol_date
+// and latest of a release or, for findings, whether any is EOL and the EOL
+// date and latest version of the one reaching EOL first.
+func githubOutputs(response []byte) (outputs map[string]string, err error) {
+	var envelope struct {
+		Result any `json:"result"`
+	}
+
+	if err = json.Unmarshal(response, &envelope); err != nil {
+		return
+	}
+
+	outputs = map[string]string{"is_eol": "false"}
+
+	switch v := envelope.Result.(type) {
+	case map[string]any:
+		latest, _ := v["latest"].(map[string]any) //nolint:errcheck // ok
+		outputs["is_eol"] = fmt.Sprint(releaseStatus(v) == StatusEol)
+		outputs["eol_date"], outputs["latest"] = getString(v, "eolFrom"), getString(latest, "name")
+	case []any:
+		for _, x := range v {
+			item, _ := x.(map[string]any) //nolint:errcheck // ok
+			if getString(item, "status") == StatusEol {
+				outputs["is_eol"] = "true"
+			}
+
+			if date := getString(item, "eolFrom"); date != "" && (outputs["eol_date"] == "" || date < outputs["eol_date"]) {
+				outputs["eol_date"], outputs["latest"] = date, getString(item, "latest")
+			}
+		}
+	}
+
+	return
+}
+
+// githubProperty escapes an annotation property (file, title) value.
+func githubProperty(s string) string {
+	return strings.NewReplacer(",", "%2C", ":", "%3A").Replace(githubData(s))
+}
+
+// githubData escapes an annotation message.
+func githubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// appendFile opens fname for appending (creating it if needed) and calls fn.
+func appendFile(fname string, fn func(f *os.File) error) (err error) {
+	f, err := os.OpenFile(fname, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec,mnd // like Actions' own files
+	if err != nil {
+		return
+	}
+
+	if err = fn(f); err != nil {
+		f.Close() //nolint:errcheck,gosec // the first error wins
+		return
+	}
+
+	return f.Close()
+}
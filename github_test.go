@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClientGitHub(t *testing.T) {
+	t.Parallel()
+
+	inv := filepath.Join("testdata", "inventory", "eol-inventory.json")
+	k8s := filepath.Join("testdata", "scan", "k8s", "app.yaml")
+
+	//nolint:govet // ok
+	cases := []struct {
+		args   []string
+		golden string
+		expErr error
+	}{
+		{[]string{"release", "go", "1.22"}, "release_eol", nil},
+		{[]string{"release", "go", "1.24.3"}, "release_maintained", nil},
+		{[]string{"release", "ubuntu", "22.04"}, "release_eoas", nil},
+		{[]string{"release", "postgresql", "13", "--within", "6mo"}, "release_approaching", nil},
+		{[]string{"latest", "golang"}, "latest", nil},
+		{[]string{"inventory", "check", "--inventory", inv}, "inventory_check", errInventoryCheck},
+		{[]string{"scan", "k8s", k8s}, "scan_k8s", errEolFound},
+		{[]string{"index"}, "", errUnsupportedFormat},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(append(tc.args, "-f", "github", "--as-of", "2025-06-01"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			dir := t.TempDir()
+			buf := &bytes.Buffer{}
+			c.sink, c.errSink, c.httpClient = buf, &bytes.Buffer{}, &mockHTTPClient{}
+			c.githubOutput, c.githubSummary = filepath.Join(dir, "output"), filepath.Join(dir, "summary")
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			for _, f := range []string{c.githubSummary, c.githubOutput} {
+				content, err := os.ReadFile(f)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				buf.WriteString("\n--- " + filepath.Base(f) + "\n")
+				buf.Write(content)
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "github", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected\n%s\ngot\n%s", exp, x)
+			}
+		})
+	}
+}
+
+func TestGithubProperty(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct{ in, exp string }{
+		{"go 1.22", "go 1.22"},
+		{"a,b:c", "a%2Cb%3Ac"},
+		{"100%\r\n", "100%25%0D%0A"},
+	}
+
+	for _, tc := range cases {
+		if act := githubProperty(tc.in); act != tc.exp {
+			t.Fatalf("Expected %q, got %q", tc.exp, act)
+		}
+	}
+}
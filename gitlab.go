@@ -0,0 +1,141 @@
+package main
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// codeQualityIssue is an issue of a GitLab Code Quality report, a subset of
+// the CodeClimate spec.
+type codeQualityIssue struct {
+	Type        string               `json:"type"`
+	CheckName   string               `json:"check_name"`
+	Description string               `json:"description"`
+	Fingerprint string               `json:"fingerprint"`
+	Severity    string               `json:"severity"`
+	Location    *codeQualityLocation `json:"location,omitempty"`
+}
+
+type codeQualityLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+// Code Quality severities, by status.
+const (
+	SeverityMajor = "major" // EOL.
+	SeverityMinor = "minor" // EOAS or unmaintained.
+	SeverityInfo  = "info"  // Approaching EOL, or unknown.
+)
+
+// Commands supporting -f gitlab-codequality: those responding with findings.
+//
+//nolint:gochecknoglobals // ok
+var codeQualityCommands = []string{"scan", "host", "inventory-check"}
+
+// gitlabCodeQuality responds with the findings as a GitLab Code Quality
+// report: a JSON array of issues, one per finding not plainly maintained.
+func (c *client) gitlabCodeQuality() (err error) {
+	if !slices.Contains(codeQualityCommands, c.command) {
+		return fmt.Errorf("%w 'gitlab-codequality' for %s", errUnsupportedFormat, c.command)
+	}
+
+	var envelope struct {
+		Result []struct {
+			finding
+
+			Owner       string `json:"owner"`
+			Environment string `json:"environment"`
+		} `json:"result"`
+	}
+
+	if err = json.Unmarshal(c.response, &envelope); err != nil {
+		return
+	}
+
+	issues := []codeQualityIssue{}
+
+	for _, r := range envelope.Result {
+		f, scope := r.finding, ""
+
+		// Inventory entries are located in the inventory, by its first line,
+		// so they are told apart by their environment and owner instead.
+		if c.command == "inventory-check" {
+			f.File, f.Line, scope = cmp.Or(c.inventoryFile, DefaultInventory), 1, r.Environment+"\x00"+r.Owner
+		}
+
+		issue, ok := c.codeQualityIssue(f, scope)
+		if !ok {
+			continue
+		}
+
+		// Host binaries are outside the repository, GitLab cannot link them.
+		if c.command == "host" {
+			issue.Location = nil
+		}
+
+		issues = append(issues, issue)
+	}
+
+	out, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return
+	}
+
+	_, err = c.sink.Write(append(out, '\n'))
+
+	return
+}
+
+// codeQualityIssue maps an evaluated finding to an issue, if it is one. The
+// scope, if any, sets apart the fingerprints of findings in the same place.
+func (c *client) codeQualityIssue(f finding, scope string) (issue codeQualityIssue, ok bool) {
+	name := f.Product + " " + f.Version
+
+	issue.CheckName = f.Status
+
+	switch {
+	case f.Status == StatusEol:
+		issue.Severity, issue.Description = SeverityMajor, name+" is EOL"
+		if f.EolFrom != "" {
+			issue.Description += " since " + f.EolFrom
+		}
+	case f.Status == StatusEoas:
+		issue.Severity, issue.Description = SeverityMinor, name+" is out of active support, EOL "+cmp.Or(f.EolFrom, "unknown")
+	case f.Status == StatusUnmaintained:
+		issue.Severity, issue.Description = SeverityMinor, name+" is no longer maintained"
+	case f.Status == StatusUnknownProduct:
+		issue.Severity, issue.Description = SeverityInfo, f.Product+" is not a known product"
+	case f.Status == StatusUnknownRelease:
+		issue.Severity, issue.Description = SeverityInfo, name+" matches no known release"
+	case f.EolFrom != "" && c.approachingEol(f.EolFrom):
+		issue.CheckName, issue.Severity, issue.Description = "approaching_eol", SeverityInfo, name+" reaches EOL on "+f.EolFrom
+	default:
+		return
+	}
+
+	if f.Latest != "" {
+		issue.Description += ", latest is " + f.Latest
+	}
+
+	product, release := cmp.Or(f.Resolved, f.Product), cmp.Or(f.Release, f.Version)
+
+	key := product + "\x00" + release + "\x00" + f.File
+	if scope != "" {
+		key += "\x00" + scope
+	}
+
+	sum := sha256.Sum256([]byte(key))
+
+	issue.Type, issue.Fingerprint = "issue", hex.EncodeToString(sum[:])
+	issue.Location = &codeQualityLocation{Path: f.File}
+	issue.Location.Lines.Begin = max(f.Line, 1)
+
+	return issue, true
+}
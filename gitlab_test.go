@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClientGitLabCodeQuality(t *testing.T) {
+	t.Parallel()
+
+	inv := filepath.Join("testdata", "inventory", "eol-inventory.json")
+	k8s := filepath.Join("testdata", "scan", "k8s", "app.yaml")
+
+	//nolint:govet // ok
+	cases := []struct {
+		args   []string
+		golden string
+		expErr error
+	}{
+		{[]string{"scan", "k8s", k8s, "--within", "6mo"}, "scan_k8s", errEolFound},
+		{[]string{"inventory", "check", "--inventory", inv}, "inventory_check", errInventoryCheck},
+		{[]string{"scan", "k8s", "-"}, "empty", nil},
+		{[]string{"release", "go", "1.22"}, "", errUnsupportedFormat},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(append(tc.args, "-f", "gitlab-codequality", "--as-of", "2025-06-01"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.errSink, c.httpClient, c.stdin = buf, &bytes.Buffer{}, &mockHTTPClient{}, &bytes.Buffer{}
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "gitlab", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected\n%s\ngot\n%s", exp, x)
+			}
+		})
+	}
+}
+
+func TestClientGitLabCodeQualityFingerprints(t *testing.T) {
+	t.Parallel()
+
+	inv := filepath.Join(t.TempDir(), "eol-inventory.json")
+	entries := `{"entries": [{"product": "go", "version": "1.22", "environment": "prod"},` +
+		`{"product": "go", "version": "1.22", "environment": "staging"}]}`
+
+	if err := os.WriteFile(inv, []byte(entries), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	c, err := newClient([]string{"inventory", "check", "--inventory", inv, "-f", "gitlab-codequality", "--as-of", "2025-06-01"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	c.sink, c.errSink, c.httpClient = buf, &bytes.Buffer{}, &mockHTTPClient{}
+
+	if err = c.handle(); !errors.Is(err, errInventoryCheck) {
+		t.Fatalf("Expected error %v, got %v", errInventoryCheck, err)
+	}
+
+	var issues []codeQualityIssue
+	if err = json.Unmarshal(buf.Bytes(), &issues); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(issues) != 2 || issues[0].Fingerprint == issues[1].Fingerprint {
+		t.Fatalf("Expected two issues, told apart by environment, got %+v", issues)
+	}
+}
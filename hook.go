@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+//nolint:gochecknoglobals // ok
+var (
+	// FROM [--platform=...] image [AS name].
+	dockerFromRe = regexp.MustCompile(`(?i)^\s*FROM\s+(?:--\S+\s+)*(\S+)(?:\s+AS\s+(\S+))?`)
+
+	// Dockerfiles, by name: Dockerfile, Containerfile, Dockerfile.dev, app.Dockerfile.
+	dockerfileRe = regexp.MustCompile(`^(?:(?:Dockerfile|Containerfile)(?:\..+)?|.+\.(?:Dockerfile|Containerfile))$`)
+)
+
+// hook is the pre-commit entry point: it scans the files it is given (the
+// staged ones) with the detectors of the scan commands and fails if any of
+// them introduces an EOL version, i.e. one that HEAD's copy did not pin.
+func (c *client) hook() (err error) {
+	findings := []finding{}
+
+	for _, path := range c.args {
+		content, err := os.ReadFile(path) //nolint:gosec // user supplied path, on purpose
+		if err != nil {
+			return err
+		}
+
+		current, err := c.detectFile(path, content)
+		if err != nil {
+			return err
+		} else if len(current) == 0 {
+			continue
+		}
+
+		previous := map[[2]string]bool{}
+
+		if old, ok := c.headContent(path); ok {
+			olds, err := c.detectFile(path, old)
+			if err != nil {
+				return err
+			}
+
+			for _, f := range olds {
+				previous[[2]string{f.Product, f.Version}] = true
+			}
+		}
+
+		for _, f := range current {
+			f.New = !previous[[2]string{f.Product, f.Version}]
+			findings = append(findings, f)
+		}
+	}
+
+	if err = c.evaluateFindings(findings); err != nil {
+		return
+	}
+
+	if c.response, err = json.Marshal(map[string]any{"total": len(findings), "result": findings}); err != nil {
+		return
+	}
+
+	var introduced []string
+
+	for _, f := range findings {
+		if f.New && f.Status == StatusEol {
+			introduced = append(introduced, fmt.Sprintf("%s %s (%s)", f.Product, f.Version, f.File))
+		} else if f.Status == StatusEol {
+			c.logf(LogVerbose, "hook: %s:%d: %s %s is EOL, but not new", f.File, f.Line, f.Product, f.Version)
+		}
+	}
+
+	if len(introduced) > 0 {
+		c.deferredErr = fmt.Errorf("%w: introduced %s", errEolFound, strings.Join(introduced, ", "))
+	}
+
+	return
+}
+
+// detectFile runs the detectors of the scan commands (dir, deps, terraform,
+// and Dockerfile base images) for the file at path, by its name.
+func (c *client) detectFile(path string, content []byte) (findings []finding, err error) {
+	name := filepath.Base(path)
+
+	switch {
+	case fileDetectors[name] != nil:
+		findings = fileDetectors[name](content)
+	case depsDetectors[name] != nil:
+		findings, err = c.resolveDeps(depsDetectors[name](content))
+	case dockerfileRe.MatchString(name):
+		findings, err = c.imageFindings(dockerfileImages(content))
+	case filepath.Ext(name) == ".tf":
+		findings = c.scanHCL(content)
+	default:
+		c.logf(LogVerbose, "hook: %s: no detector", path)
+	}
+
+	for i := range findings {
+		findings[i].File = filepath.ToSlash(path)
+	}
+
+	return
+}
+
+// dockerfileImages returns the base images of a Dockerfile's stages, less
+// the earlier stages and the images given through build args.
+func dockerfileImages(content []byte) (images []imageRef) {
+	var stages []string
+
+	eachLine(content, func(line string, n int) bool {
+		m := dockerFromRe.FindStringSubmatch(line)
+		if m == nil {
+			return true
+		}
+
+		if image := m[1]; !strings.Contains(image, "$") && !slices.Contains(stages, strings.ToLower(image)) {
+			images = append(images, imageRef{Image: image, Line: n, Source: "FROM"})
+		}
+
+		if m[2] != "" {
+			stages = append(stages, strings.ToLower(m[2]))
+		}
+
+		return true
+	})
+
+	return
+}
+
+// headContent returns the content of path as of HEAD, if it is tracked.
+func (c *client) headContent(path string) (content []byte, ok bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultCommandTimeout)
+	defer cancel()
+
+	content, err := c.runner.Run(ctx, "git", "show", "HEAD:./"+filepath.ToSlash(path))
+	if err != nil {
+		c.logf(LogVerbose, "hook: %s: new file (%v)", path, err)
+		return nil, false
+	}
+
+	return content, true
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// gitRunner serves git show HEAD:<path>, from its contents by path.
+type gitRunner map[string]string
+
+func (m gitRunner) LookPath(file string) (string, error) { return "/usr/bin/" + file, nil }
+
+func (m gitRunner) Run(_ context.Context, _ string, args ...string) ([]byte, error) {
+	out, ok := m[args[len(args)-1]]
+	if !ok {
+		return nil, exec.ErrNotFound
+	}
+
+	return []byte(out), nil
+}
+
+func TestClientHook(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join("testdata", "hook", "app")
+	file := func(name string) string { return filepath.Join(dir, name) }
+
+	head := gitRunner{
+		"HEAD:./" + filepath.ToSlash(file("go.mod")):           "module example.com/app\n\ngo 1.22\n",
+		"HEAD:./" + filepath.ToSlash(file("Dockerfile")):       "FROM golang:1.24 AS build\nFROM python:3.12-slim\n",
+		"HEAD:./" + filepath.ToSlash(file("requirements.txt")): "django==3.2.25\n",
+	}
+
+	//nolint:govet // ok
+	cases := []struct {
+		args   []string
+		golden string
+		expErr error
+	}{
+		{[]string{file("go.mod"), file("Dockerfile"), file(".nvmrc"), file("requirements.txt"), file("README.md")}, "introduced", errEolFound},
+		{[]string{file("go.mod"), file("requirements.txt")}, "existing", nil},
+		{[]string{file("Dockerfile"), "-f", "json"}, "dockerfile_json", errEolFound},
+		{[]string{file("README.md")}, "none", nil},
+		{[]string{file("missing.txt")}, "", os.ErrNotExist},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(append([]string{"hook", "--as-of", "2025-06-01"}, tc.args...))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.errSink, c.httpClient, c.runner = buf, &bytes.Buffer{}, &mockHTTPClient{}, head
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "hook", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected\n%s\ngot\n%s", exp, x)
+			}
+		})
+	}
+}
+
+func TestDockerfileImages(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("ARG V=3.12\nFROM --platform=linux/amd64 golang:1.24-alpine AS Build\n" +
+		"from build\nFROM python:${V}\nFROM nginx:1.27 as web\nFROM web\n")
+
+	var act []string
+	for _, img := range dockerfileImages(content) {
+		act = append(act, img.Image)
+	}
+
+	if exp := []string{"golang:1.24-alpine", "nginx:1.27"}; !slices.Equal(act, exp) {
+		t.Fatalf("Expected %v, got %v", exp, act)
+	}
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// commandRunner runs the products' version commands. It is an interface
+// (like httpClient) so that tests need not depend on what is installed.
+type commandRunner interface {
+	LookPath(file string) (string, error)
+	Run(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+type execRunner struct{}
+
+// productCommand is a product's version command, split into arguments.
+type productCommand struct {
+	Product string
+	Path    string
+	Args    []string
+}
+
+// DefaultCommandTimeout is how long a version command may run, by default.
+const DefaultCommandTimeout = 5 * time.Second
+
+//nolint:gochecknoglobals // ok
+var (
+	hostVersionRe = regexp.MustCompile(`\d+\.\d+(?:\.\d+)*`)
+
+	// The only arguments a version command may have: the commands come from
+	// the API, so anything else (i.e. rm -rf ~) is refused.
+	versionFlags = []string{"--version", "-version", "-v", "-V", "version"}
+)
+
+func (execRunner) LookPath(file string) (string, error) { return exec.LookPath(file) }
+
+func (execRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	// Some tools (i.e. java -version) print their version to stderr.
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+// host runs the version commands of the given products (or, if none, of all
+// the products whose command is on PATH) and reports the installed versions.
+func (c *client) host() (err error) {
+	timeout := DefaultCommandTimeout
+	if c.timeout != "" {
+		if timeout, err = time.ParseDuration(c.timeout); err != nil {
+			return fmt.Errorf("%w: invalid --timeout: %w", errUsage, err)
+		}
+	}
+
+	commands, err := c.hostCommands()
+	if err != nil {
+		return
+	}
+
+	var findings []finding
+
+	for _, pc := range commands {
+		f, ok := c.runVersionCommand(pc, timeout)
+		if ok {
+			findings = append(findings, f)
+		}
+	}
+
+	return c.respondFindings(findings)
+}
+
+// hostCommands returns the version commands to run: those of the products
+// given as arguments, or else those of all products found on PATH. The
+// latter skips the binaries used by several products' commands (java, cat,
+// npm), which can't tell which one is installed.
+func (c *client) hostCommands() (commands []productCommand, err error) {
+	if len(c.args) > 0 {
+		for _, name := range c.args {
+			err = c.withProduct(name, func(pn string) error {
+				product, err := c.fetchProduct(pn)
+				if err != nil {
+					return err
+				}
+
+				if pc, ok := c.lookupCommand(pn, getString(product, "versionCommand"), nil); ok {
+					commands = append(commands, pc)
+				} else {
+					c.logf(LogNormal, "host: %s: no version command found on PATH", pn)
+				}
+
+				return nil
+			})
+			if err != nil {
+				return
+			}
+		}
+
+		return
+	}
+
+	body, err := c.fetch("/products/full")
+	if err != nil {
+		return
+	}
+
+	var envelope struct {
+		Result []struct {
+			Name           string `json:"name"`
+			VersionCommand string `json:"versionCommand"`
+		} `json:"result"`
+	}
+
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return
+	}
+
+	users := map[string]map[string]bool{}
+
+	for _, p := range envelope.Result {
+		for _, line := range commandLines(p.VersionCommand) {
+			bin, _, _ := strings.Cut(line, " ")
+			if users[bin] == nil {
+				users[bin] = map[string]bool{}
+			}
+
+			users[bin][p.Name] = true
+		}
+	}
+
+	shared := map[string]bool{}
+	for bin, products := range users {
+		shared[bin] = len(products) > 1
+	}
+
+	for _, p := range envelope.Result {
+		if pc, ok := c.lookupCommand(p.Name, p.VersionCommand, shared); ok {
+			commands = append(commands, pc)
+		}
+	}
+
+	return
+}
+
+// lookupCommand picks the first of the (alternative) commands given by
+// versionCommand that is a plain version command (a binary, by name, and a
+// version flag) and whose binary is on PATH, and not one of the skipped ones.
+func (c *client) lookupCommand(product, versionCommand string, skip map[string]bool) (pc productCommand, ok bool) {
+	for _, line := range commandLines(versionCommand) {
+		args, simple := splitCommand(line)
+		if !simple || len(args) == 0 {
+			c.logf(LogVerbose, "host: %s: skipping %q, needs a shell", product, line)
+			continue
+		}
+
+		if !versionOnly(args) {
+			c.logf(LogVerbose, "host: %s: skipping %q, not a plain version command", product, line)
+			continue
+		}
+
+		if skip[args[0]] {
+			continue
+		}
+
+		path, err := c.runner.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+
+		return productCommand{Product: product, Path: path, Args: args}, true
+	}
+
+	return
+}
+
+// runVersionCommand runs the command and parses the version it prints.
+func (c *client) runVersionCommand(pc productCommand, timeout time.Duration) (f finding, ok bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := strings.Join(pc.Args, " ")
+
+	out, err := c.runner.Run(ctx, pc.Path, pc.Args[1:]...)
+	if err != nil {
+		c.logf(LogNormal, "host: %s: %s: %v", pc.Product, cmd, err)
+		return
+	}
+
+	version := hostVersionRe.FindString(string(out))
+	if version == "" {
+		c.logf(LogNormal, "host: %s: no version in the output of %s", pc.Product, cmd)
+		return
+	}
+
+	return finding{Product: pc.Product, Version: version, File: pc.Path, Source: cmd}, true
+}
+
+// versionOnly reports whether args run a binary found on PATH with nothing
+// but a version flag.
+func versionOnly(args []string) bool {
+	return len(args) == 2 && !strings.Contains(args[0], "/") && slices.Contains(versionFlags, args[1])
+}
+
+// commandLines returns the commands of a versionCommand, which may list
+// alternatives (one per line) and comments.
+func commandLines(versionCommand string) (lines []string) {
+	for line := range strings.SplitSeq(versionCommand, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+
+	return
+}
+
+// splitCommand splits a command line into arguments, honoring quotes. It
+// reports whether the command is simple, i.e. free of pipes, redirections,
+// variables and the like, which would need a shell.
+func splitCommand(line string) (args []string, simple bool) {
+	var (
+		arg     strings.Builder
+		quote   rune
+		started bool
+	)
+
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == '\'':
+			arg.WriteRune(r)
+		case quote == '"':
+			if strings.ContainsRune("$`\\", r) {
+				return nil, false
+			}
+
+			arg.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, started = r, true
+		case r == ' ' || r == '\t':
+			if started {
+				args, started = append(args, arg.String()), false
+				arg.Reset()
+			}
+		case strings.ContainsRune("|&;<>()$`\\*?[]{}~", r):
+			return nil, false
+		default:
+			arg.WriteRune(r)
+
+			started = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, false
+	}
+
+	if started {
+		args = append(args, arg.String())
+	}
+
+	return args, true
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// mockRunner "installs" the binaries it has an output for, under /usr/bin.
+type mockRunner map[string]string
+
+func (m mockRunner) LookPath(file string) (string, error) {
+	if _, ok := m[file]; !ok {
+		return "", exec.ErrNotFound
+	}
+
+	return "/usr/bin/" + file, nil
+}
+
+func (m mockRunner) Run(_ context.Context, name string, _ ...string) ([]byte, error) {
+	out := m[filepath.Base(name)]
+	if out == "" {
+		return nil, exec.ErrNotFound
+	}
+
+	return []byte(out), nil
+}
+
+func TestClientHost(t *testing.T) {
+	t.Parallel()
+
+	runner := mockRunner{
+		"go":      "go version go1.22.5 linux/amd64\n",
+		"python3": "Python 3.8.10\n",
+		"java":    `openjdk version "11.0.22" 2024-01-16` + "\n",
+		"node":    "",
+	}
+
+	//nolint:govet // ok
+	cases := []struct {
+		args   []string
+		golden string
+		expErr error
+	}{
+		{[]string{"host", "--as-of", "2025-06-01"}, "all", errEolFound},
+		{[]string{"host", "go", "python", "--as-of", "2025-06-01", "-f", "json"}, "products_json", errEolFound},
+		{[]string{"host", "go", "--as-of", "2025-06-01", "-f", "gitlab-codequality"}, "go_gitlab", errEolFound},
+		{[]string{"host", "nodejs", "ruby"}, "none", nil},
+		{[]string{"host", "golng"}, "", errNotFound},
+		{[]string{"host", "--timeout", "soon"}, "", errUsage},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.errSink, c.httpClient, c.runner = buf, &bytes.Buffer{}, &mockHTTPClient{}, runner
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "host", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected %q, got %q", exp, x)
+			}
+		})
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		line   string
+		exp    []string
+		simple bool
+	}{
+		{"go version", []string{"go", "version"}, true},
+		{`psql -c "SELECT version();"`, []string{"psql", "-c", "SELECT version();"}, true},
+		{"docker version --format '{{.Server.Version}}'", []string{"docker", "version", "--format", "{{.Server.Version}}"}, true},
+		{"composer show api-platform/core | grep versions", nil, false},
+		{"${KAFKA_HOME}/bin/kafka-topics.sh --version", nil, false},
+		{`echo "$HOME"`, nil, false},
+		{`echo "unterminated`, nil, false},
+	}
+
+	for _, tc := range cases {
+		args, simple := splitCommand(tc.line)
+		if simple != tc.simple || !reflect.DeepEqual(args, tc.exp) {
+			t.Fatalf("Expected %q (%v) for %q, got %q (%v)", tc.exp, tc.simple, tc.line, args, simple)
+		}
+	}
+}
+
+func TestLookupCommand(t *testing.T) {
+	t.Parallel()
+
+	c, err := newClient([]string{"host"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	c.errSink, c.runner = &bytes.Buffer{}, mockRunner{"go": "", "rm": "", "psql": "", "version.sh": ""}
+
+	cases := []struct {
+		versionCommand string
+		exp            []string
+	}{
+		{"go version", []string{"go", "version"}},
+		{"rm -rf /tmp/app\ngo version", []string{"go", "version"}},
+		{"rm -rf ~", nil},
+		{"rm --no-preserve-root -r /", nil},
+		{"rm version extra", nil},
+		{"go", nil},
+		{`psql -c "SELECT version();"`, nil},
+		{"./bin/version.sh --version", nil},
+	}
+
+	for _, tc := range cases {
+		pc, ok := c.lookupCommand("test", tc.versionCommand, nil)
+		if ok != (tc.exp != nil) || !reflect.DeepEqual(pc.Args, tc.exp) {
+			t.Fatalf("Expected %q for %q, got %q (%v)", tc.exp, tc.versionCommand, pc.Args, ok)
+		}
+	}
+}
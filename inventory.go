@@ -0,0 +1,320 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// inventory is the list of product versions in use, as read from --inventory.
+type inventory struct {
+	Entries []inventoryEntry `json:"entries"`
+}
+
+type inventoryEntry struct {
+	Product     string `json:"product"`
+	Version     string `json:"version"`
+	Owner       string `json:"owner,omitempty"`
+	Environment string `json:"environment,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+}
+
+// inventoryResult is an inventory entry, evaluated.
+type inventoryResult struct {
+	inventoryEntry
+	evaluation
+}
+
+// evaluation is the status of a product version, as resolved by evaluate.
+type evaluation struct {
+	Resolved      string   `json:"resolved,omitempty"`
+	Release       string   `json:"release,omitempty"`
+	Status        string   `json:"status"`
+	EolFrom       string   `json:"eolFrom,omitempty"`
+	Latest        string   `json:"latest,omitempty"`
+	Suggestions   []string `json:"suggestions,omitempty"`
+	PatchesBehind int      `json:"patchesBehind,omitempty"`
+	IsOutdated    bool     `json:"isOutdated"`
+}
+
+// inventoryGroup summarizes the results sharing an owner or environment.
+type inventoryGroup struct {
+	Counts  map[string]int    `json:"counts"`
+	Name    string            `json:"name"`
+	Entries []inventoryResult `json:"entries"`
+	Total   int               `json:"total"`
+}
+
+// Statuses of inventory entries that could not be resolved.
+const (
+	StatusUnknownProduct = "unknown_product"
+	StatusUnknownRelease = "unknown_release"
+)
+
+// Inventory defaults.
+const (
+	DefaultInventory = "eol-inventory.json"
+	defaultGroupBy   = "owner"
+	unassigned       = "unassigned"
+)
+
+func (c *client) inventory() (err error) {
+	fname := cmp.Or(c.inventoryFile, DefaultInventory)
+	sub, args := c.args[0], c.args[1:]
+
+	// JSON only: YAML would take a dependency.
+	if ext := filepath.Ext(fname); ext == ".yaml" || ext == ".yml" {
+		return fmt.Errorf("%w: inventory %s: only JSON inventories are supported", errUsage, fname)
+	}
+
+	switch sub {
+	case "check":
+		c.command = "inventory-check"
+		err = c.inventoryCheck(fname)
+	case "report":
+		c.command = "inventory-report"
+		err = c.inventoryReport(fname)
+	case "add":
+		if len(args) != 2 { //nolint:mnd // product and version
+			return fmt.Errorf("%w: inventory add requires <product> <version>", errUsage)
+		}
+
+		err = c.inventoryAdd(fname, inventoryEntry{
+			Product: args[0], Version: args[1], Owner: c.owner, Environment: c.env, Notes: c.notes,
+		})
+	case "remove":
+		if len(args) != 2 { //nolint:mnd // product and version
+			return fmt.Errorf("%w: inventory remove requires <product> <version>", errUsage)
+		}
+
+		err = c.inventoryRemove(fname, args[0], args[1])
+	default:
+		err = fmt.Errorf("%w: unknown inventory command %q (check, report, add or remove)", errUsage, sub)
+	}
+
+	return
+}
+
+func (c *client) inventoryCheck(fname string) (err error) {
+	results, err := c.evaluateInventory(fname)
+	if err != nil {
+		return
+	}
+
+	if c.response, err = json.Marshal(map[string]any{"total": len(results), "result": results}); err != nil {
+		return
+	}
+
+	var failed int
+
+	for _, r := range results {
+		if r.Status == StatusEol || r.Status == StatusUnknownProduct || r.Status == StatusUnknownRelease {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		c.deferredErr = fmt.Errorf("%w: %d of %d entries are EOL or unknown", errInventoryCheck, failed, len(results))
+	}
+
+	return
+}
+
+func (c *client) inventoryReport(fname string) (err error) {
+	groupBy := cmp.Or(c.groupBy, defaultGroupBy)
+	if groupBy != "owner" && groupBy != "environment" {
+		return fmt.Errorf("%w: --group-by must be owner or environment", errUsage)
+	}
+
+	results, err := c.evaluateInventory(fname)
+	if err != nil {
+		return
+	}
+
+	c.response, err = json.Marshal(map[string]any{"result": map[string]any{
+		"groupBy": groupBy, "total": len(results), "groups": groupInventory(results, groupBy),
+	}})
+
+	return
+}
+
+// groupInventory groups the results by owner or environment, by name.
+func groupInventory(results []inventoryResult, groupBy string) (groups []inventoryGroup) {
+	idx := map[string]*inventoryGroup{}
+
+	for _, r := range results {
+		name := r.Owner
+		if groupBy == "environment" {
+			name = r.Environment
+		}
+
+		name = cmp.Or(name, unassigned)
+
+		g, ok := idx[name]
+		if !ok {
+			g = &inventoryGroup{Name: name, Counts: map[string]int{}}
+			idx[name] = g
+		}
+
+		g.Entries = append(g.Entries, r)
+		g.Counts[r.Status]++
+		g.Total++
+	}
+
+	for _, name := range sortedKeys(idx) {
+		groups = append(groups, *idx[name])
+	}
+
+	return
+}
+
+func (c *client) evaluateInventory(fname string) (results []inventoryResult, err error) {
+	inv, err := readInventory(fname)
+	if err != nil {
+		return
+	}
+
+	for _, e := range inv.Entries {
+		r := inventoryResult{inventoryEntry: e}
+		if r.evaluation, err = c.evaluate(e.Product, e.Version); err != nil {
+			return
+		}
+
+		results = append(results, r)
+	}
+
+	return
+}
+
+// evaluate resolves product and version through the same alias resolution
+// and version fallback as the release command. Unknown products and releases
+// are reported as such (see Status), rather than as errors.
+func (c *client) evaluate(product, version string) (ev evaluation, err error) {
+	var rel map[string]any
+
+	err = c.withProduct(product, func(pn string) (err error) {
+		rel, _, err = c.lookupRelease(pn, version)
+		ev.Resolved = pn
+
+		return
+	})
+
+	var prodErr *ProductNotFoundError
+
+	switch {
+	case errors.As(err, &prodErr):
+		ev.Status, ev.Resolved, ev.Suggestions = StatusUnknownProduct, "", prodErr.Suggestions
+	case errors.Is(err, errReleaseNotFound):
+		ev.Status = StatusUnknownRelease
+	case err != nil:
+		return
+	default:
+		latest, _ := rel["latest"].(map[string]any) //nolint:errcheck // ok
+		ev.Release, ev.Status, ev.EolFrom = getString(rel, "name"), releaseStatus(rel), getString(rel, "eolFrom")
+		ev.Latest = getString(latest, "name")
+		ev.IsOutdated, _ = rel["isOutdated"].(bool)      //nolint:errcheck // ok
+		ev.PatchesBehind, _ = rel["patchesBehind"].(int) //nolint:errcheck // ok
+	}
+
+	if ev.Resolved == product {
+		ev.Resolved = ""
+	}
+
+	return ev, nil
+}
+
+func (c *client) inventoryAdd(fname string, e inventoryEntry) (err error) {
+	inv, err := readInventory(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		err = nil
+	} else if err != nil {
+		return
+	}
+
+	// Validate the entry, so that typos don't make it into the inventory.
+	if err = c.withProduct(e.Product, func(pn string) (err error) {
+		_, _, err = c.lookupRelease(pn, e.Version)
+		return
+	}); err != nil {
+		return
+	}
+
+	same := func(x inventoryEntry) bool {
+		return x.Product == e.Product && x.Version == e.Version && x.Environment == e.Environment
+	}
+
+	if i := slices.IndexFunc(inv.Entries, same); i >= 0 {
+		inv.Entries[i] = e
+		c.logf(LogNormal, "inventory: updated %s", e)
+	} else {
+		inv.Entries = append(inv.Entries, e)
+		c.logf(LogNormal, "inventory: added %s", e)
+	}
+
+	return writeInventory(fname, inv)
+}
+
+func (c *client) inventoryRemove(fname, product, version string) (err error) {
+	inv, err := readInventory(fname)
+	if err != nil {
+		return
+	}
+
+	n := len(inv.Entries)
+	inv.Entries = slices.DeleteFunc(inv.Entries, func(x inventoryEntry) bool {
+		return x.Product == product && x.Version == version && (c.env == "" || x.Environment == c.env)
+	})
+
+	if len(inv.Entries) == n {
+		return fmt.Errorf("inventory entry %s %s %w", product, version, errNotFound)
+	}
+
+	c.logf(LogNormal, "inventory: removed %d entries", n-len(inv.Entries))
+
+	return writeInventory(fname, inv)
+}
+
+func (e inventoryEntry) String() string {
+	var parts []string
+	if e.Environment != "" {
+		parts = append(parts, e.Environment)
+	}
+
+	if e.Owner != "" {
+		parts = append(parts, "owned by "+e.Owner)
+	}
+
+	s := e.Product + " " + e.Version
+	if len(parts) > 0 {
+		s += " (" + strings.Join(parts, ", ") + ")"
+	}
+
+	return s
+}
+
+func readInventory(fname string) (inv inventory, err error) {
+	body, err := os.ReadFile(fname) //nolint:gosec // user supplied path, on purpose
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(body, &inv); err != nil {
+		return inv, fmt.Errorf("invalid inventory %s: %w", fname, err)
+	}
+
+	return
+}
+
+func writeInventory(fname string, inv inventory) (err error) {
+	body, err := json.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return
+	}
+
+	return os.WriteFile(fname, append(body, '\n'), 0o644) //nolint:gosec,mnd // meant to be committed
+}
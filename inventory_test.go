@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestClientInventory(t *testing.T) {
+	t.Parallel()
+
+	inv := filepath.Join("testdata", "inventory", "eol-inventory.json")
+
+	//nolint:govet // ok
+	cases := []struct {
+		args   []string
+		golden string
+		expErr error
+	}{
+		{[]string{"inventory", "check", "--inventory", inv, "--as-of", "2025-06-01"}, "check", errInventoryCheck},
+		{[]string{"inventory", "check", "--inventory", inv, "--as-of", "2025-06-01", "-f", "json"}, "check_json", errInventoryCheck},
+		{[]string{"inventory", "report", "--inventory", inv, "--as-of", "2025-06-01"}, "report", nil},
+		{[]string{"inventory", "report", "--inventory", inv, "--as-of", "2025-06-01", "--group-by", "environment"}, "report_environment", nil},
+		{[]string{"inventory", "report", "--inventory", inv, "--group-by", "team"}, "", errUsage},
+		{[]string{"inventory", "check", "--inventory", "testdata/inventory/missing.json"}, "", os.ErrNotExist},
+		{[]string{"inventory", "add", "go"}, "", errUsage},
+		{[]string{"inventory", "remove"}, "", errUsage},
+		{[]string{"inventory", "bogus"}, "", errUsage},
+		{[]string{"inventory", "check", "--inventory", "eol-inventory.yaml"}, "", errUsage},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.httpClient = buf, &mockHTTPClient{}
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "inventory", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected %q, got %q", exp, x)
+			}
+		})
+	}
+}
+
+func TestClientInventoryEdit(t *testing.T) {
+	t.Parallel()
+
+	fname := filepath.Join(t.TempDir(), "eol-inventory.json")
+	run := func(expErr error, args ...string) {
+		t.Helper()
+
+		c, err := newClient(append(args, "--inventory", fname))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		c.sink, c.errSink, c.httpClient = &bytes.Buffer{}, &bytes.Buffer{}, &mockHTTPClient{}
+
+		if err = c.handle(); !errors.Is(err, expErr) {
+			t.Fatalf("Expected error %v, got %v", expErr, err)
+		}
+	}
+
+	run(nil, "inventory", "add", "go", "1.24", "--owner", "platform", "--env", "prod")
+	run(nil, "inventory", "add", "ubuntu", "22.04", "--env", "prod")
+	run(nil, "inventory", "add", "go", "1.24", "--owner", "data", "--env", "prod", "--notes", "moved")
+	run(errReleaseNotFound, "inventory", "add", "go", "0.9")
+	run(errNotFound, "inventory", "add", "golng", "1.24")
+	run(nil, "inventory", "remove", "ubuntu", "22.04")
+	run(errNotFound, "inventory", "remove", "ubuntu", "22.04")
+
+	inv, err := readInventory(fname)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	exp := []inventoryEntry{{Product: "go", Version: "1.24", Owner: "data", Environment: "prod", Notes: "moved"}}
+	if !slices.Equal(inv.Entries, exp) {
+		t.Fatalf("Expected %v, got %v", exp, inv.Entries)
+	}
+}
+
+func TestGroupInventory(t *testing.T) {
+	t.Parallel()
+
+	results := []inventoryResult{
+		{inventoryEntry: inventoryEntry{Owner: "b"}, evaluation: evaluation{Status: StatusEol}},
+		{inventoryEntry: inventoryEntry{Owner: "a"}, evaluation: evaluation{Status: StatusMaintained}},
+		{evaluation: evaluation{Status: StatusUnknownProduct}},
+		{inventoryEntry: inventoryEntry{Owner: "b"}, evaluation: evaluation{Status: StatusEol}},
+	}
+
+	groups := groupInventory(results, "owner")
+	if len(groups) != 3 || groups[0].Name != "a" || groups[1].Name != "b" || groups[2].Name != unassigned {
+		t.Fatalf("Unexpected groups: %v", groups)
+	}
+
+	if g := groups[1]; g.Total != 2 || g.Counts[StatusEol] != 2 {
+		t.Fatalf("Unexpected group: %v", g)
+	}
+}
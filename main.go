@@ -18,18 +18,26 @@ func main() {
 	)
 
 	defer func() {
+		if err == nil {
+			return
+		}
+
 		switch {
-		case err == nil:
+		case c != nil && c.format == FormatJSON:
+			os.Stderr.Write(errorJSON(err)) //nolint:errcheck,gosec // ok
 		case errors.Is(err, errUsage):
 			msg := err.Error()
 			msg, _ = strings.CutPrefix(msg, "usage error: ")
-			fmt.Printf("Error: %v!\n\n", msg)
-			c.printUsage()
-			os.Exit(1)
+			fmt.Fprintf(os.Stderr, "Error: %v!\n", msg)
+
+			if c != nil && c.logLevel > LogQuiet {
+				fmt.Fprint(os.Stderr, "\n"+helpText)
+			}
 		default:
-			fmt.Printf("Error: %v!\n", err)
-			os.Exit(2)
+			fmt.Fprintf(os.Stderr, "Error: %v!\n", err)
 		}
+
+		os.Exit(exitCode(err))
 	}()
 
 	c, err = newClient(os.Args[1:])
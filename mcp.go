@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// rpcMessage is a JSON-RPC 2.0 request or notification (no ID), as read by
+// the MCP server.
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+}
+
+// mcpTool is a tool exposed by the MCP server. Its arguments are passed to
+// run in the order of the schema's required properties.
+type mcpTool struct {
+	run         func(c *client, args []string) error
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	required    []string
+}
+
+type toolContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	StructuredContent map[string]any `json:"structuredContent,omitempty"`
+	Content           []toolContent  `json:"content"`
+	IsError           bool           `json:"isError"`
+}
+
+// JSON-RPC error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// MCP protocol versions, latest first.
+//
+//nolint:gochecknoglobals // ok
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// Maximum size of a message read by the MCP server.
+const maxMCPMessage = 4 << 20
+
+// mcpTools returns the tools of the MCP server, mirroring the commands.
+func mcpTools() []mcpTool {
+	product := func(c *client, args []string, fn func(pn string) error) error {
+		return c.withProduct(args[0], fn)
+	}
+
+	tools := []mcpTool{
+		{
+			Name:        "products",
+			Description: "List all the products tracked by endoflife.date, with their category, tags and aliases.",
+			run:         func(c *client, _ []string) error { return c.doRequest("/products") },
+		},
+		{
+			Name:        "product",
+			Description: "Get a product with all its releases and their support and end-of-life dates.",
+			required:    []string{"product"},
+			run: func(c *client, args []string) error {
+				return product(c, args, func(pn string) error { return c.doRequest("/products/" + pn) })
+			},
+		},
+		{
+			Name: "release",
+			Description: "Get the release of a product matching a version (i.e. 18, 18.20 or 18.20.4), " +
+				"with whether it is still supported, end-of-life, and how many patches behind the latest it is.",
+			required: []string{"product", "version"},
+			run: func(c *client, args []string) error {
+				return product(c, args, func(pn string) error { return c.release(pn, args[1]) })
+			},
+		},
+		{
+			Name:        "latest",
+			Description: "Get the latest release of a product.",
+			required:    []string{"product"},
+			run: func(c *client, args []string) error {
+				return product(c, args, func(pn string) error { return c.doRequest("/products/" + pn + "/releases/latest") })
+			},
+		},
+		{
+			Name:        "category",
+			Description: "List the products of a category (i.e. lang, os, framework, database).",
+			required:    []string{"category"},
+			run:         func(c *client, args []string) error { return c.doRequest("/categories/" + args[0]) },
+		},
+		{
+			Name:        "tag",
+			Description: "List the products with a tag (i.e. google, microsoft, linux-distribution).",
+			required:    []string{"tag"},
+			run:         func(c *client, args []string) error { return c.doRequest("/tags/" + args[0]) },
+		},
+		{
+			Name:        "identifier",
+			Description: "List the identifiers (i.e. purl, cpe) of a type and the products they map to.",
+			required:    []string{"type"},
+			run:         func(c *client, args []string) error { return c.doRequest("/identifiers/" + args[0]) },
+		},
+	}
+
+	for i, t := range tools {
+		props := map[string]any{}
+		for _, name := range t.required {
+			props[name] = map[string]string{"type": "string", "description": mcpArgDescriptions[name]}
+		}
+
+		tools[i].InputSchema = map[string]any{"type": "object", "properties": props, "required": append([]string{}, t.required...)}
+	}
+
+	return tools
+}
+
+//nolint:gochecknoglobals // ok
+var mcpArgDescriptions = map[string]string{
+	"product":  "Product name or alias, i.e. nodejs, go, ubuntu",
+	"version":  "Version to look up, i.e. 18, 1.22.3 or 22.04",
+	"category": "Category name",
+	"tag":      "Tag name",
+	"type":     "Identifier type, i.e. purl, cpe",
+}
+
+// mcp serves the Model Context Protocol over stdio: one JSON-RPC message per
+// line on stdin, responses on stdout (diagnostics go to stderr).
+func (c *client) mcp() (err error) {
+	tools := mcpTools()
+
+	sc := bufio.NewScanner(c.stdin)
+	sc.Buffer(nil, maxMCPMessage)
+	enc := json.NewEncoder(c.sink)
+
+	for sc.Scan() {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+
+		if resp := c.mcpHandle(sc.Bytes(), tools); resp != nil {
+			if err = enc.Encode(resp); err != nil {
+				return
+			}
+		}
+	}
+
+	return sc.Err()
+}
+
+// mcpHandle handles one message, returning the response or, for
+// notifications, nil.
+func (c *client) mcpHandle(line []byte, tools []mcpTool) *rpcResponse {
+	var msg rpcMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return rpcFail(json.RawMessage("null"), rpcParseError, err.Error())
+	}
+
+	if msg.ID == nil {
+		c.logf(LogVerbose, "mcp: notification %s", msg.Method)
+		return nil
+	}
+
+	if msg.JSONRPC != "2.0" || msg.Method == "" {
+		return rpcFail(msg.ID, rpcInvalidRequest, "invalid JSON-RPC 2.0 request")
+	}
+
+	c.logf(LogVerbose, "mcp: %s", msg.Method)
+
+	switch msg.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+
+		json.Unmarshal(msg.Params, &params) //nolint:errcheck,gosec // a missing version gets the latest
+
+		pv := mcpProtocolVersions[0]
+		if slices.Contains(mcpProtocolVersions, params.ProtocolVersion) {
+			pv = params.ProtocolVersion
+		}
+
+		return rpcOK(msg.ID, map[string]any{
+			"protocolVersion": pv,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      map[string]string{"name": "eol", "version": version},
+		})
+	case "ping":
+		return rpcOK(msg.ID, map[string]any{})
+	case "tools/list":
+		return rpcOK(msg.ID, map[string]any{"tools": tools})
+	case "tools/call":
+		return c.mcpCall(msg, tools)
+	default:
+		return rpcFail(msg.ID, rpcMethodNotFound, "method not found: "+msg.Method)
+	}
+}
+
+// mcpCall runs a tool. Failures of the tool itself (i.e. unknown product)
+// are reported in its result, for the model to see, rather than as errors.
+func (c *client) mcpCall(msg rpcMessage, tools []mcpTool) *rpcResponse {
+	var params struct {
+		Arguments map[string]any `json:"arguments"`
+		Name      string         `json:"name"`
+	}
+
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return rpcFail(msg.ID, rpcInvalidParams, err.Error())
+	}
+
+	i := slices.IndexFunc(tools, func(t mcpTool) bool { return t.Name == params.Name })
+	if i < 0 {
+		return rpcFail(msg.ID, rpcInvalidParams, "unknown tool: "+params.Name)
+	}
+
+	tool, rc := tools[i], c.requestClient()
+
+	for _, name := range tool.required {
+		v, _ := params.Arguments[name].(string) //nolint:errcheck // checked below
+		if v == "" {
+			return rpcOK(msg.ID, toolError(fmt.Errorf("%w: %s requires the %s argument", errUsage, tool.Name, name)))
+		}
+
+		rc.args = append(rc.args, v)
+	}
+
+	rc.command = tool.Name
+
+	result, err := rc.toolResponse(tool)
+	if err != nil {
+		return rpcOK(msg.ID, toolError(err))
+	}
+
+	text, err := json.Marshal(result)
+	if err != nil {
+		return rpcOK(msg.ID, toolError(err))
+	}
+
+	return rpcOK(msg.ID, toolResult{
+		StructuredContent: map[string]any{"result": result},
+		Content:           []toolContent{{Type: "text", Text: string(text)}},
+	})
+}
+
+// toolResponse runs the tool and returns the result of its response.
+func (c *client) toolResponse(tool mcpTool) (result any, err error) {
+	if err = tool.run(c, c.args); err != nil {
+		return
+	}
+
+	var envelope struct {
+		Result any `json:"result"`
+	}
+
+	err = json.Unmarshal(c.response, &envelope)
+
+	return envelope.Result, err
+}
+
+func toolError(err error) toolResult {
+	return toolResult{Content: []toolContent{{Type: "text", Text: string(bytes.TrimSpace(errorJSON(err)))}}, IsError: true}
+}
+
+func rpcOK(id json.RawMessage, result any) *rpcResponse {
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Result: result}
+}
+
+func rpcFail(id json.RawMessage, code int, message string) *rpcResponse {
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
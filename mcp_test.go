@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClientMCP(t *testing.T) {
+	t.Parallel()
+
+	call := func(id, tool, args string) string {
+		return `{"jsonrpc":"2.0","id":` + id + `,"method":"tools/call","params":{"name":"` + tool + `","arguments":` + args + `}}`
+	}
+
+	cases := []struct {
+		golden   string
+		messages []string
+	}{
+		{"handshake", []string{
+			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+			`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+			`{"jsonrpc":"2.0","id":"two","method":"ping"}`,
+			`{"jsonrpc":"2.0","id":3,"method":"initialize","params":{"protocolVersion":"1999-01-01"}}`,
+		}},
+		{"tools_list", []string{`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`}},
+		{"tools_call", []string{
+			call("1", "release", `{"product":"go","version":"1.22"}`),
+			call("2", "latest", `{"product":"golang"}`),
+			call("3", "tag", `{"tag":"lang"}`),
+		}},
+		{"errors", []string{
+			call("1", "release", `{"product":"golng","version":"1"}`),
+			call("2", "release", `{"product":"go","version":"1"}`),
+			call("3", "product", `{}`),
+			call("4", "bogus", `{}`),
+			`{"jsonrpc":"2.0","id":5,"method":"resources/list"}`,
+			`{"jsonrpc":"2.0","id":6}`,
+			`{not json`,
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient([]string{"mcp", "--as-of", "2025-06-01"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.errSink, c.httpClient = buf, &bytes.Buffer{}, &mockHTTPClient{}
+			c.stdin = strings.NewReader(strings.Join(tc.messages, "\n") + "\n")
+
+			if err = c.handle(); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "mcp", tc.golden))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if act := buf.String(); act != string(exp) {
+				t.Fatalf("Expected\n%s\ngot\n%s", exp, act)
+			}
+		})
+	}
+}
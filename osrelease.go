@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+//nolint:gochecknoglobals // ok
+var (
+	// Products, by os-release ID.
+	osProducts = map[string]string{
+		"ubuntu": "ubuntu", "debian": "debian", "rhel": "rhel", "centos": "centos", "alpine": "alpine-linux",
+		"amzn": "amazon-linux", "rocky": "rocky-linux", "almalinux": "almalinux", "fedora": "fedora",
+		"ol": "oracle-linux", "opensuse-leap": "opensuse", "sles": "sles", "linuxmint": "linuxmint",
+		"pop": "pop-os", "photon": "photon",
+	}
+
+	// Where os-release lives, relative to the root filesystem.
+	osReleasePaths = []string{"etc/os-release", "usr/lib/os-release"}
+)
+
+// osRelease detects the OS release from os-release (at path, or under it if
+// a directory, i.e. a container's root filesystem) and responds with its
+// release, like the release command.
+func (c *client) osRelease() (err error) {
+	path := "/"
+	if len(c.args) > 0 {
+		path = c.args[0]
+	}
+
+	fields, err := readOSRelease(path)
+	if err != nil {
+		return
+	}
+
+	pn, version, codename := osReleaseProduct(fields)
+	if pn == "" {
+		return fmt.Errorf("os %q %w, see ID in %s", fields["ID"], errNotFound, path)
+	}
+
+	// Respond as release <product> <version> would.
+	release := func(version string) error {
+		c.command, c.args = "release", []string{pn, version}
+		return c.release(pn, version)
+	}
+
+	c.logf(LogVerbose, "os: %s %s (%s) is %s", fields["ID"], fields["VERSION_ID"], codename, pn)
+
+	if version != "" {
+		if err = release(version); !errors.Is(err, errReleaseNotFound) || codename == "" {
+			return
+		}
+	}
+
+	if codename == "" {
+		return fmt.Errorf("%w: no VERSION_ID or VERSION_CODENAME in %s", errReleaseNotFound, path)
+	}
+
+	if version, err = c.codenameRelease(pn, codename); err != nil {
+		return
+	}
+
+	return release(version)
+}
+
+// codenameRelease returns the name of the release of product pn that goes
+// by the given (os-release) codename.
+func (c *client) codenameRelease(pn, codename string) (name string, err error) {
+	product, err := c.fetchProduct(pn)
+	if err != nil {
+		return
+	}
+
+	for _, r := range toSlice(product["releases"]) {
+		r, _ := r.(map[string]any) //nolint:errcheck // ok
+		if matchCodename(getString(r, "codename"), codename) {
+			name = getString(r, "name")
+			c.logf(LogVerbose, "os: codename %s is %s %s", codename, pn, name)
+
+			return
+		}
+	}
+
+	return "", &ReleaseNotFoundError{Product: pn, Variants: []string{codename}}
+}
+
+// osReleaseProduct maps the os-release fields to a product, along with the
+// version and codename to look its release up by. Derivatives of a known
+// distribution (ID_LIKE) are mapped to it by the codename of the release
+// they are based on (i.e. UBUNTU_CODENAME), as their own versions differ.
+func osReleaseProduct(fields map[string]string) (pn, version, codename string) {
+	id := fields["ID"]
+	if pn = osProducts[id]; pn != "" {
+		if id == "centos" && strings.Contains(fields["NAME"], "Stream") {
+			pn = "centos-stream"
+		}
+
+		return pn, fields["VERSION_ID"], fields["VERSION_CODENAME"]
+	}
+
+	for like := range strings.FieldsSeq(fields["ID_LIKE"]) {
+		if cn := fields[strings.ToUpper(like)+"_CODENAME"]; cn != "" && osProducts[like] != "" {
+			return osProducts[like], "", cn
+		}
+	}
+
+	return "", "", ""
+}
+
+// matchCodename reports whether a release's codename ("Jammy Jellyfish",
+// "Bookworm") is the os-release one ("jammy", "bookworm").
+func matchCodename(releaseCodename, codename string) bool {
+	first, _, _ := strings.Cut(releaseCodename, " ")
+	return first != "" && strings.EqualFold(first, codename)
+}
+
+// readOSRelease reads os-release from path, or from under path if it is a
+// directory, into a map of its fields.
+func readOSRelease(path string) (fields map[string]string, err error) {
+	if fi, serr := os.Stat(path); serr == nil && fi.IsDir() {
+		root := path
+		if path = filepath.Join(root, osReleasePaths[0]); !fileExists(path) {
+			path = filepath.Join(root, osReleasePaths[1])
+		}
+	}
+
+	content, err := os.ReadFile(path) //nolint:gosec // user supplied path, on purpose
+	if err != nil {
+		return
+	}
+
+	return parseOSRelease(content), nil
+}
+
+// parseOSRelease parses the KEY=value lines of os-release, values being
+// optionally quoted.
+func parseOSRelease(content []byte) map[string]string {
+	fields := map[string]string{}
+
+	eachLine(content, func(line string, _ int) bool {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			return true
+		}
+
+		if v, err := strconv.Unquote(value); err == nil && strings.HasPrefix(value, `"`) {
+			value = v
+		} else {
+			value = strings.Trim(value, `"'`)
+		}
+
+		fields[key] = value
+
+		return true
+	})
+
+	return fields
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
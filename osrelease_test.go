@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClientOSRelease(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join("testdata", "os", "root")
+
+	//nolint:govet // ok
+	cases := []struct {
+		args   []string
+		golden string
+		expErr error
+	}{
+		{[]string{"os", filepath.Join(root, "ubuntu-os-release"), "--as-of", "2025-06-01"}, "ubuntu", nil},
+		{[]string{"os", filepath.Join(root, "alpine"), "--as-of", "2025-06-01"}, "alpine", nil},
+		{[]string{"os", filepath.Join(root, "distroless"), "--as-of", "2025-06-01", "-f", "json"}, "distroless_json", nil},
+		{[]string{"os", filepath.Join(root, "debian-testing-os-release"), "--as-of", "2025-06-01"}, "debian-testing", nil},
+		{[]string{"os", filepath.Join(root, "zorin-os-release"), "--as-of", "2025-06-01"}, "zorin", nil},
+		{[]string{"os", filepath.Join(root, "gentoo-os-release")}, "", errNotFound},
+		{[]string{"os", filepath.Join(root, "missing")}, "", os.ErrNotExist},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.httpClient = buf, &mockHTTPClient{}
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "os", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected %q, got %q", exp, x)
+			}
+		})
+	}
+}
+
+func TestParseOSRelease(t *testing.T) {
+	t.Parallel()
+
+	fields := parseOSRelease([]byte("# comment\nID=ubuntu\nNAME=\"Ubuntu \\\"LTS\\\"\"\nVERSION_ID='22.04'\nbogus\n"))
+	exp := map[string]string{"ID": "ubuntu", "NAME": `Ubuntu "LTS"`, "VERSION_ID": "22.04"}
+
+	if len(fields) != len(exp) {
+		t.Fatalf("Expected %v, got %v", exp, fields)
+	}
+
+	for k, v := range exp {
+		if fields[k] != v {
+			t.Fatalf("Expected %s=%q, got %q", k, v, fields[k])
+		}
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// finding is a product version detected by one of the scanners.
+type finding struct {
+	evaluation
+
+	Product string `json:"product"`
+	Version string `json:"version"`
+	File    string `json:"file"`
+	Source  string `json:"source"`
+	Line    int    `json:"line,omitempty"`
+	New     bool   `json:"new,omitempty"` // Not pinned by HEAD's copy (hook).
+}
+
+// detector finds the product versions pinned in the content of a file.
+type detector func(content []byte) []finding
+
+//nolint:gochecknoglobals // ok
+var (
+	versionRe = regexp.MustCompile(`\d+(?:\.\d+)*`)
+
+	packageJSONNodeRe = regexp.MustCompile(`"node"\s*:\s*"[^"\d]*(\d+(?:\.\d+)*)`)
+	globalJSONSDKRe   = regexp.MustCompile(`"version"\s*:\s*"(\d+\.\d+)`)
+	pomReleaseRe      = regexp.MustCompile(`<(?:maven\.compiler\.release|maven\.compiler\.target|maven\.compiler\.source|java\.version|release)>` +
+		`\s*(?:1\.)?(\d+)\s*<`)
+
+	// Well known files pinning runtime versions, by file name.
+	fileDetectors = map[string]detector{
+		"go.mod":             detectLines(regexp.MustCompile(`^go\s+(\d+\.\d+)(?:\.\d+)?\s*$`), "go", "go directive"),
+		".nvmrc":             detectVersionFile("nodejs"),
+		".node-version":      detectVersionFile("nodejs"),
+		"package.json":       detectPackageJSON,
+		".python-version":    detectVersionFile("python"),
+		"pyproject.toml":     detectLines(regexp.MustCompile(`^\s*requires-python\s*=\s*["'][^"'\d]*(\d+(?:\.\d+)*)`), "python", "requires-python"),
+		".ruby-version":      detectVersionFile("ruby"),
+		".terraform-version": detectVersionFile("terraform"),
+		".tool-versions":     detectToolVersions,
+		"Gemfile":            detectLines(regexp.MustCompile(`^\s*ruby\s+["'][^"'\d]*(\d+(?:\.\d+)*)`), "ruby", "ruby directive"),
+		"pom.xml":            detectPom,
+		"global.json":        detectGlobalJSON,
+	}
+
+	// asdf plugins with an endoflife.date product, by plugin name.
+	asdfPlugins = map[string]string{
+		"nodejs": "nodejs", "python": "python", "ruby": "ruby", "golang": "go", "terraform": "terraform",
+		"java": "java", "dotnet": "dotnet", "dotnet-core": "dotnet", "php": "php", "elixir": "elixir",
+		"erlang": "erlang", "kubectl": "kubernetes", "postgres": "postgresql", "redis": "redis",
+	}
+
+	// Java distributions, by asdf version prefix.
+	javaDistributions = map[string]string{
+		"temurin": "eclipse-temurin", "adoptopenjdk": "eclipse-temurin", "corretto": "amazon-corretto",
+		"zulu": "azul-zulu", "microsoft": "microsoft-build-of-openjdk", "oracle": "oracle-jdk", "openjdk": "openjdk-builds-from-oracle",
+	}
+
+	// Directories that never hold the project's own version pins.
+	skipDirs = []string{"node_modules", "vendor", "venv", "testdata"}
+)
+
+func (c *client) scan() (err error) {
+	kind, path := c.args[0], "."
+	if len(c.args) > 1 {
+		path = c.args[1]
+	}
+
+	var findings []finding
+
+	switch kind {
+	case "dir":
+		findings, err = scanDir(path)
+	case "k8s":
+		findings, err = c.scanK8s(path)
+	case "terraform":
+		findings, err = c.scanTerraform(path)
+	case "deps":
+		findings, err = c.scanDeps(path)
+	case "image":
+		if len(c.args) < 2 { //nolint:mnd // target and tarball
+			return fmt.Errorf("%w: scan image requires <image.tar>", errUsage)
+		}
+
+		findings, err = c.scanImage(path)
+	default:
+		err = fmt.Errorf("%w: unknown scan target %q (dir, deps, image, k8s, terraform)", errUsage, kind)
+	}
+
+	if err != nil {
+		return
+	}
+
+	return c.respondFindings(findings)
+}
+
+// respondFindings evaluates the findings and responds with them. Like
+// inventory check, the command fails if any of them is EOL.
+func (c *client) respondFindings(findings []finding) (err error) {
+	if findings == nil {
+		findings = []finding{}
+	}
+
+	if err = c.evaluateFindings(findings); err != nil {
+		return
+	}
+
+	if c.response, err = json.Marshal(map[string]any{"total": len(findings), "result": findings}); err != nil {
+		return
+	}
+
+	var eol int
+
+	for _, f := range findings {
+		if f.Status == StatusEol {
+			eol++
+		}
+	}
+
+	if eol > 0 {
+		c.deferredErr = fmt.Errorf("%w: %d of %d findings are EOL", errEolFound, eol, len(findings))
+	}
+
+	return
+}
+
+// evaluateFindings evaluates the findings, in place.
+func (c *client) evaluateFindings(findings []finding) (err error) {
+	cache := map[[2]string]evaluation{}
+
+	for i, f := range findings {
+		key := [2]string{f.Product, f.Version}
+
+		ev, ok := cache[key]
+		if !ok {
+			if ev, err = c.evaluate(f.Product, f.Version); err != nil {
+				return
+			}
+
+			cache[key] = ev
+		}
+
+		findings[i].evaluation = ev
+	}
+
+	return
+}
+
+// scanDir walks root and runs the detectors for the files they know of.
+func scanDir(root string) ([]finding, error) {
+	return scanFiles(root, fileDetectors)
+}
+
+// scanFiles walks root and runs detectors on the files they are registered
+// for, by name. Findings are reported with their path relative to root.
+func scanFiles(root string, detectors map[string]detector) (findings []finding, err error) {
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(name, ".") || slices.Contains(skipDirs, name)) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		detect, ok := detectors[name]
+		if !ok {
+			return nil
+		}
+
+		content, err := os.ReadFile(path) //nolint:gosec // walking the user supplied tree, on purpose
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(root, path) //nolint:errcheck // path is within root
+
+		for _, f := range detect(content) {
+			f.File = filepath.ToSlash(rel)
+			findings = append(findings, f)
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// detectLines returns a detector reporting product for every line matching
+// re, with the version taken from its first submatch.
+func detectLines(re *regexp.Regexp, product, source string) detector {
+	return func(content []byte) (findings []finding) {
+		eachLine(content, func(line string, n int) bool {
+			if m := re.FindStringSubmatch(line); m != nil {
+				findings = append(findings, finding{Product: product, Version: m[1], Source: source, Line: n})
+			}
+
+			return true
+		})
+
+		return
+	}
+}
+
+// detectVersionFile returns a detector for files holding nothing but the
+// version, i.e. .nvmrc, possibly decorated (v18.17.0, ruby-3.2.2).
+func detectVersionFile(product string) detector {
+	return func(content []byte) (findings []finding) {
+		eachLine(content, func(line string, n int) bool {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				return true
+			}
+
+			if v := versionRe.FindString(line); v != "" {
+				findings = append(findings, finding{Product: product, Version: v, Source: "version file", Line: n})
+			}
+
+			return false
+		})
+
+		return
+	}
+}
+
+// detectToolVersions reads asdf's .tool-versions, i.e. "nodejs 18.17.0".
+func detectToolVersions(content []byte) (findings []finding) {
+	eachLine(content, func(line string, n int) bool {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") { //nolint:mnd // tool and version
+			return true
+		}
+
+		product, version := asdfPlugins[fields[0]], fields[1]
+		if product == "" {
+			return true
+		}
+
+		if product == "java" {
+			dist, v, _ := strings.Cut(version, "-")
+			if product = javaDistributions[dist]; product == "" {
+				return true
+			}
+
+			version = v
+		}
+
+		if v := versionRe.FindString(version); v != "" {
+			findings = append(findings, finding{Product: product, Version: v, Source: "asdf " + fields[0], Line: n})
+		}
+
+		return true
+	})
+
+	return
+}
+
+func detectPackageJSON(content []byte) []finding {
+	var pkg struct {
+		Engines map[string]string `json:"engines"`
+	}
+
+	if json.Unmarshal(content, &pkg) != nil || pkg.Engines["node"] == "" {
+		return nil
+	}
+
+	return firstLine(content, packageJSONNodeRe, "nodejs", "engines.node")
+}
+
+// detectPom reads the Java release targeted by a Maven build (1.8 being 8),
+// checked against eclipse-temurin as the build names no JDK vendor.
+func detectPom(content []byte) []finding {
+	return firstLine(content, pomReleaseRe, "eclipse-temurin", "Java release")
+}
+
+// detectGlobalJSON reads the .NET SDK pinned in global.json. SDK versions
+// (8.0.100) don't follow the runtime's patches, so only the release is kept:
+// major.minor, or just major where minor is 0, as releases are named (8, 3.1).
+func detectGlobalJSON(content []byte) []finding {
+	var global struct {
+		SDK struct {
+			Version string `json:"version"`
+		} `json:"sdk"`
+	}
+
+	if json.Unmarshal(content, &global) != nil || global.SDK.Version == "" {
+		return nil
+	}
+
+	findings := firstLine(content, globalJSONSDKRe, "dotnet", "sdk.version")
+	for i, f := range findings {
+		findings[i].Version = strings.TrimSuffix(f.Version, ".0")
+	}
+
+	return findings
+}
+
+// firstLine is like detectLines, but only reports the first match.
+func firstLine(content []byte, re *regexp.Regexp, product, source string) []finding {
+	if findings := detectLines(re, product, source)(content); len(findings) > 0 {
+		return findings[:1]
+	}
+
+	return nil
+}
+
+// eachLine calls fn for every line of content, with its (1 based) number,
+// for as long as fn returns true.
+func eachLine(content []byte, fn func(line string, n int) bool) {
+	sc := bufio.NewScanner(bytes.NewReader(content))
+	for n := 1; sc.Scan(); n++ {
+		if !fn(sc.Text(), n) {
+			return
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+//nolint:gochecknoglobals // ok
+var (
+	// Lockfiles (and the like), by file name. Their detectors report the
+	// packages' purls as Source, for scanDeps to map them to products.
+	depsDetectors = map[string]detector{
+		"package-lock.json": detectPackageLock,
+		"pnpm-lock.yaml":    detectPnpmLock,
+		"poetry.lock":       detectPoetryLock,
+		"requirements.txt":  detectRequirements,
+		"Gemfile.lock":      detectGemfileLock,
+		"composer.lock":     detectComposerLock,
+		"go.sum":            detectGoSum,
+	}
+
+	// Packages standing for a product, but not (yet) listed by its purl
+	// identifiers, i.e. Laravel's is the application skeleton's.
+	purlAliases = map[string]string{
+		"pkg:composer/laravel/framework": "laravel",
+	}
+
+	goMajorRe = regexp.MustCompile(`/v\d+$`)
+)
+
+// scanDeps walks root for lockfiles and reports the packages in use that are
+// products on their own (frameworks, mostly), found through their purls.
+func (c *client) scanDeps(root string) (findings []finding, err error) {
+	deps, err := scanFiles(root, depsDetectors)
+	if err != nil {
+		return
+	}
+
+	return c.resolveDeps(deps)
+}
+
+// resolveDeps maps the packages found by the deps detectors to products,
+// skipping those that are not products and duplicates (within a file).
+func (c *client) resolveDeps(deps []finding) (findings []finding, err error) {
+	if len(deps) == 0 {
+		return
+	}
+
+	index, err := c.purlIndex()
+	if err != nil {
+		return
+	}
+
+	seen := map[[3]string]bool{}
+
+	for _, d := range deps {
+		purl := d.Source
+
+		d.Product = lookupPurl(index, purl)
+		if d.Product == "" {
+			continue
+		}
+
+		key := [3]string{d.File, d.Product, d.Version}
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		d.Source = strings.TrimPrefix(purl, "pkg:")
+		findings = append(findings, d)
+	}
+
+	return
+}
+
+// lookupPurl finds the product of a package purl (without version). Go
+// modules are looked up with and without their major version suffix.
+func lookupPurl(index map[string]string, purl string) string {
+	purl = strings.ToLower(purl)
+
+	for _, p := range []string{purl, goMajorRe.ReplaceAllString(purl, "")} {
+		if product := index[p]; product != "" {
+			return product
+		}
+
+		if product := purlAliases[p]; product != "" {
+			return product
+		}
+	}
+
+	return ""
+}
+
+// dep returns a finding for the package with the given purl, to be resolved
+// by scanDeps, or nothing if version holds no version.
+func dep(purl, version string, line int) []finding {
+	if v := versionRe.FindString(version); v != "" {
+		return []finding{{Version: v, Source: purl, Line: line}}
+	}
+
+	return nil
+}
+
+// npmPurl returns the purl of an npm package, scoped ones' @ encoded.
+func npmPurl(name string) string {
+	return "pkg:npm/" + strings.Replace(name, "@", "%40", 1)
+}
+
+// detectPackageLock reads npm's package-lock.json: the packages map of
+// lockfile v2 and v3, or the (nested) dependencies of v1.
+func detectPackageLock(content []byte) (findings []finding) {
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]npmDependency `json:"dependencies"`
+	}
+
+	if json.Unmarshal(content, &lock) != nil {
+		return nil
+	}
+
+	for path, p := range lock.Packages {
+		i := strings.LastIndex(path, "node_modules/")
+		if i < 0 {
+			continue
+		}
+
+		findings = append(findings, dep(npmPurl(path[i+len("node_modules/"):]), p.Version, lineOf(content, `"`+path+`"`))...)
+	}
+
+	if len(lock.Packages) == 0 {
+		findings = lockV1Deps(content, lock.Dependencies)
+	}
+
+	sortFindings(findings)
+
+	return
+}
+
+type npmDependency struct {
+	Dependencies map[string]npmDependency `json:"dependencies"`
+	Version      string                   `json:"version"`
+}
+
+func lockV1Deps(content []byte, deps map[string]npmDependency) (findings []finding) {
+	for name, d := range deps {
+		findings = append(findings, dep(npmPurl(name), d.Version, lineOf(content, `"`+name+`"`))...)
+		findings = append(findings, lockV1Deps(content, d.Dependencies)...)
+	}
+
+	return
+}
+
+// detectPnpmLock reads the packages of pnpm-lock.yaml, keyed name@version
+// (lockfile v9) or /name@version (v6), possibly with peer suffixes.
+func detectPnpmLock(content []byte) (findings []finding) {
+	var inPackages bool
+
+	eachLine(content, func(line string, n int) bool {
+		if line == "" {
+			return true
+		}
+
+		if !strings.HasPrefix(line, " ") {
+			inPackages = strings.TrimSpace(line) == "packages:"
+			return true
+		}
+
+		if !inPackages || strings.HasPrefix(line, "   ") || !strings.HasSuffix(line, ":") {
+			return true
+		}
+
+		key := strings.Trim(strings.TrimSpace(strings.TrimSuffix(line, ":")), `'"`)
+		key, _, _ = strings.Cut(strings.TrimPrefix(key, "/"), "(")
+
+		if i := strings.LastIndex(key, "@"); i > 0 {
+			findings = append(findings, dep(npmPurl(key[:i]), key[i+1:], n)...)
+		}
+
+		return true
+	})
+
+	return
+}
+
+// detectPoetryLock reads the name and version of poetry.lock's packages.
+func detectPoetryLock(content []byte) (findings []finding) {
+	var (
+		name string
+		line int
+	)
+
+	eachLine(content, func(l string, n int) bool {
+		switch {
+		case strings.HasPrefix(l, "[[package]]"):
+			name = ""
+		case strings.HasPrefix(l, "name = "):
+			name, line = strings.Trim(strings.TrimPrefix(l, "name = "), `"`), n
+		case strings.HasPrefix(l, "version = ") && name != "":
+			findings = append(findings, dep(pypiPurl(name), strings.Trim(strings.TrimPrefix(l, "version = "), `"`), line)...)
+			name = ""
+		}
+
+		return true
+	})
+
+	return
+}
+
+// detectRequirements reads the exact (==) pins of a requirements.txt.
+func detectRequirements(content []byte) (findings []finding) {
+	eachLine(content, func(line string, n int) bool {
+		line, _, _ = strings.Cut(line, "#")
+		line, _, _ = strings.Cut(line, ";")
+
+		name, version, ok := strings.Cut(line, "==")
+		if !ok {
+			return true
+		}
+
+		name, _, _ = strings.Cut(strings.TrimSpace(name), "[")
+		findings = append(findings, dep(pypiPurl(name), strings.TrimSpace(version), n)...)
+
+		return true
+	})
+
+	return
+}
+
+// pypiPurl returns the purl of a Python package, its name normalized.
+func pypiPurl(name string) string {
+	return "pkg:pypi/" + strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+}
+
+// detectGemfileLock reads the gems of Gemfile.lock's specs, i.e. "rails (7.1.3)".
+func detectGemfileLock(content []byte) (findings []finding) {
+	eachLine(content, func(line string, n int) bool {
+		if !strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "     ") {
+			return true
+		}
+
+		name, version, ok := strings.Cut(strings.TrimSpace(line), " (")
+		if ok {
+			findings = append(findings, dep("pkg:gem/"+name, version, n)...)
+		}
+
+		return true
+	})
+
+	return
+}
+
+// detectComposerLock reads the packages (and dev packages) of composer.lock.
+func detectComposerLock(content []byte) (findings []finding) {
+	type pkg struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	var lock struct {
+		Packages    []pkg `json:"packages"`
+		PackagesDev []pkg `json:"packages-dev"`
+	}
+
+	if json.Unmarshal(content, &lock) != nil {
+		return nil
+	}
+
+	for _, p := range append(lock.Packages, lock.PackagesDev...) {
+		findings = append(findings, dep("pkg:composer/"+p.Name, p.Version, lineOf(content, `"name": "`+p.Name+`"`))...)
+	}
+
+	return
+}
+
+// detectGoSum reads the modules of go.sum, skipping the go.mod only entries
+// of the versions considered, but not used, by the build.
+func detectGoSum(content []byte) (findings []finding) {
+	eachLine(content, func(line string, n int) bool {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") { //nolint:mnd // module, version and hash
+			return true
+		}
+
+		findings = append(findings, dep("pkg:golang/"+fields[0], fields[1], n)...)
+
+		return true
+	})
+
+	return
+}
+
+// sortFindings sorts findings by line, as read from maps they are not.
+func sortFindings(findings []finding) {
+	slices.SortFunc(findings, func(a, b finding) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), strings.Compare(a.Source, b.Source))
+	})
+}
+
+// lineOf returns the (1 based) line of the first occurrence of s in content,
+// or 0 if there is none.
+func lineOf(content []byte, s string) int {
+	i := bytes.Index(content, []byte(s))
+	if i < 0 {
+		return 0
+	}
+
+	return bytes.Count(content[:i], []byte("\n")) + 1
+}
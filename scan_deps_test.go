@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestClientScanDeps(t *testing.T) {
+	t.Parallel()
+
+	deps := filepath.Join("testdata", "scan", "deps")
+
+	//nolint:govet // ok
+	cases := []struct {
+		args   []string
+		golden string
+		expErr error
+	}{
+		{[]string{"scan", "deps", deps, "--as-of", "2025-06-01"}, "deps_dir", errEolFound},
+		{[]string{"scan", "deps", deps, "--as-of", "2025-06-01", "-f", "json"}, "deps_json", errEolFound},
+		{[]string{"scan", "deps", filepath.Join(deps, "web"), "--as-of", "2025-06-01"}, "deps_web", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.httpClient = buf, &mockHTTPClient{}
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "scan", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected %q, got %q", exp, x)
+			}
+		})
+	}
+}
+
+func TestDepsDetectors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		detect  detector
+		content string
+		exp     []finding
+	}{
+		{detectPackageLock, `{"dependencies": {"vue": {"version": "2.7.16", "dependencies": {"@vue/shared": {"version": "3.4.27"}}}}}`, []finding{
+			{Version: "3.4.27", Source: "pkg:npm/%40vue/shared", Line: 1},
+			{Version: "2.7.16", Source: "pkg:npm/vue", Line: 1},
+		}},
+		{detectPnpmLock, "packages:\n\n  /vue@2.7.16(typescript@5.4.5):\n    resolution: {}\n", []finding{
+			{Version: "2.7.16", Source: "pkg:npm/vue", Line: 3},
+		}},
+		{detectRequirements, "Wagtail==6.1\n-r base.txt\nflask\n", []finding{
+			{Version: "6.1", Source: "pkg:pypi/wagtail", Line: 1},
+		}},
+		{detectGoSum, "github.com/aws/aws-cdk-go/awscdk/v2 v2.140.0 h1:x=\nexample.com/m v0.1.0/go.mod h1:y=\n", []finding{
+			{Version: "2.140.0", Source: "pkg:golang/github.com/aws/aws-cdk-go/awscdk/v2", Line: 1},
+		}},
+		{detectComposerLock, `{"packages": [`, nil},
+	}
+
+	for _, tc := range cases {
+		if got := tc.detect([]byte(tc.content)); !reflect.DeepEqual(got, tc.exp) {
+			t.Fatalf("Expected %v, got %v", tc.exp, got)
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// imageLayer is what scanImage needs of a layer: the contents of the files
+// of interest it adds, and the paths it deletes (whiteouts).
+type imageLayer struct {
+	Files   map[string][]byte
+	Deleted []string
+	Opaque  []string
+}
+
+// Image scanning limits.
+const (
+	// Outer tarball entries up to this size are kept as candidate manifests.
+	maxImageMetadata = 1 << 20
+	// Files of interest up to this size are read (dpkg's status can be large).
+	maxImageFile = 64 << 20
+)
+
+//nolint:gochecknoglobals // ok
+var (
+	// Files read from the image: os-release, runtime markers and package
+	// databases (paths within the image).
+	imageFiles = []string{
+		"etc/os-release", "usr/lib/os-release", "usr/local/go/VERSION", "usr/local/include/node/node_version.h",
+		"lib/apk/db/installed", "var/lib/dpkg/status",
+	}
+
+	// Python's standard library, os.py being there for every release.
+	pythonStdlibRe = regexp.MustCompile(`^usr/(?:local/)?lib/python(\d+\.\d+)/os\.py$`)
+	nodeDefineRe   = regexp.MustCompile(`(?m)^#define NODE_(MAJOR|MINOR|PATCH)_VERSION (\d+)`)
+
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// scanImage reads an image tarball (docker save or OCI layout) and reports
+// its OS release, language runtimes and the packages that are products on
+// their own, as found in its flattened layers.
+func (c *client) scanImage(fname string) (findings []finding, err error) {
+	files, err := c.readImage(fname)
+	if err != nil {
+		return
+	}
+
+	var osFields map[string]string
+
+	for _, p := range []string{"etc/os-release", "usr/lib/os-release"} {
+		if content, ok := files[p]; ok {
+			osFields = parseOSRelease(content)
+
+			if f, ok := c.imageOSFinding(osFields, "/"+p); ok {
+				findings = append(findings, f)
+			}
+
+			break
+		}
+	}
+
+	findings = append(findings, imageRuntimes(files)...)
+
+	pkgs, err := c.imagePackages(files, osFields)
+	if err != nil {
+		return
+	}
+
+	findings = append(findings, pkgs...)
+	slices.SortStableFunc(findings, func(a, b finding) int {
+		return cmp.Or(strings.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+
+	return
+}
+
+func (c *client) imageOSFinding(fields map[string]string, file string) (f finding, ok bool) {
+	pn, version, codename := osReleaseProduct(fields)
+	if pn == "" {
+		c.logf(LogNormal, "scan: %s: unknown OS %q", file, fields["ID"])
+		return
+	}
+
+	if version == "" && codename != "" {
+		var err error
+		if version, err = c.codenameRelease(pn, codename); err != nil {
+			c.logf(LogNormal, "scan: %s: %v", file, err)
+			return
+		}
+	}
+
+	return finding{Product: pn, Version: version, File: file, Source: "os-release"}, version != ""
+}
+
+// imageRuntimes reports the language runtimes installed the way their
+// official images do it.
+func imageRuntimes(files map[string][]byte) (findings []finding) {
+	if content, ok := files["usr/local/go/VERSION"]; ok {
+		findings = append(findings, finding{
+			Product: "go", Version: versionRe.FindString(string(content)), File: "/usr/local/go/VERSION", Source: "go VERSION",
+		})
+	}
+
+	if content, ok := files["usr/local/include/node/node_version.h"]; ok {
+		parts := map[string]string{}
+		for _, m := range nodeDefineRe.FindAllStringSubmatch(string(content), -1) {
+			parts[m[1]] = m[2]
+		}
+
+		if parts["MAJOR"] != "" {
+			findings = append(findings, finding{
+				Product: "nodejs", Version: strings.Join([]string{parts["MAJOR"], parts["MINOR"], parts["PATCH"]}, "."),
+				File: "/usr/local/include/node/node_version.h", Source: "node_version.h",
+			})
+		}
+	}
+
+	for _, p := range sortedKeys(files) {
+		if m := pythonStdlibRe.FindStringSubmatch(p); m != nil {
+			findings = append(findings, finding{Product: "python", Version: m[1], File: "/" + path.Dir(p), Source: "python stdlib"})
+		}
+	}
+
+	return
+}
+
+// imagePackages reports the installed (apk or dpkg) packages that are
+// products on their own, found through their pkg:apk or pkg:deb purls.
+func (c *client) imagePackages(files map[string][]byte, osFields map[string]string) (findings []finding, err error) {
+	var pkgs []finding
+
+	if content, ok := files["lib/apk/db/installed"]; ok {
+		pkgs = append(pkgs, packageDB(content, "P:", "V:", "", "apk", "/lib/apk/db/installed")...)
+	}
+
+	if content, ok := files["var/lib/dpkg/status"]; ok {
+		pkgs = append(pkgs, packageDB(content, "Package: ", "Version: ", "Status: ", "deb", "/var/lib/dpkg/status")...)
+	}
+
+	if len(pkgs) == 0 {
+		return
+	}
+
+	index, err := c.purlIndex()
+	if err != nil {
+		return
+	}
+
+	// Derivatives use their parent's packages (and purls).
+	distros := append([]string{osFields["ID"]}, strings.Fields(osFields["ID_LIKE"])...)
+	seen := map[[2]string]bool{}
+
+	for _, p := range pkgs {
+		for _, distro := range distros {
+			purl := strings.Replace(p.Source, "/_/", "/"+distro+"/", 1)
+			if p.Product = lookupPurl(index, purl); p.Product != "" {
+				p.Source = strings.TrimPrefix(purl, "pkg:")
+				break
+			}
+		}
+
+		key := [2]string{p.Product, p.Version}
+		if p.Product == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		findings = append(findings, p)
+	}
+
+	return
+}
+
+// packageDB reads the blank line separated stanzas of an apk or dpkg
+// database. Packages are reported with their purl as source (the distro
+// left as _), dpkg ones only if installed.
+func packageDB(content []byte, namePrefix, versionPrefix, statusPrefix, purlType, file string) (pkgs []finding) {
+	var (
+		name, version string
+		installed     bool
+		line          int
+	)
+
+	flush := func() {
+		// Drop the epoch (1:2.3-1) before looking for the version.
+		if _, v, ok := strings.Cut(version, ":"); ok {
+			version = v
+		}
+
+		if v := versionRe.FindString(version); name != "" && v != "" && (statusPrefix == "" || installed) {
+			pkgs = append(pkgs, finding{Version: v, File: file, Line: line, Source: "pkg:" + purlType + "/_/" + name})
+		}
+
+		name, version, installed = "", "", false
+	}
+
+	eachLine(content, func(l string, n int) bool {
+		switch {
+		case l == "":
+			flush()
+		case strings.HasPrefix(l, namePrefix):
+			name, line = strings.TrimPrefix(l, namePrefix), n
+		case strings.HasPrefix(l, versionPrefix):
+			version = strings.TrimPrefix(l, versionPrefix)
+		case statusPrefix != "" && strings.HasPrefix(l, statusPrefix):
+			installed = strings.HasSuffix(l, " installed")
+		}
+
+		return true
+	})
+
+	flush()
+
+	return
+}
+
+// readImage reads the files of interest from the image tarball, with its
+// layers flattened: later layers override (or delete) earlier files.
+func (c *client) readImage(fname string) (files map[string][]byte, err error) {
+	meta := map[string][]byte{}
+
+	if err = eachTarEntry(fname, func(name string, hdr *tar.Header, r io.Reader) (err error) {
+		if hdr.Typeflag == tar.TypeReg && hdr.Size <= maxImageMetadata {
+			meta[name], err = io.ReadAll(r)
+		}
+
+		return
+	}); err != nil {
+		return
+	}
+
+	order, err := imageLayerOrder(meta)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fname, err)
+	}
+
+	layers := map[string]imageLayer{}
+
+	if err = eachTarEntry(fname, func(name string, _ *tar.Header, r io.Reader) (err error) {
+		if !slices.Contains(order, name) {
+			return
+		}
+
+		layer, err := readLayer(r)
+		if err != nil {
+			return fmt.Errorf("layer %s: %w", name, err)
+		}
+
+		layers[name] = layer
+
+		return
+	}); err != nil {
+		return
+	}
+
+	files = map[string][]byte{}
+
+	for _, name := range order {
+		layer := layers[name]
+
+		for _, dir := range layer.Opaque {
+			deleteTree(files, dir)
+		}
+
+		for _, p := range layer.Deleted {
+			deleteTree(files, p)
+		}
+
+		for p, content := range layer.Files {
+			files[p] = content
+		}
+	}
+
+	return
+}
+
+// imageLayerOrder returns the tarball entries holding the layers of the
+// image, bottom first, as listed by docker save's manifest.json or else by
+// the OCI layout's index.json (for multi platform images, the first one).
+func imageLayerOrder(meta map[string][]byte) (layers []string, err error) {
+	if body, ok := meta["manifest.json"]; ok {
+		var manifests []struct {
+			Layers []string `json:"Layers"`
+		}
+
+		if err = json.Unmarshal(body, &manifests); err != nil {
+			return
+		}
+
+		if len(manifests) == 0 {
+			return nil, errInvalidImage
+		}
+
+		for _, l := range manifests[0].Layers {
+			layers = append(layers, path.Clean(l))
+		}
+
+		return
+	}
+
+	body, ok := meta["index.json"]
+	for ok {
+		var index struct {
+			Manifests []struct {
+				Digest string `json:"digest"`
+			} `json:"manifests"`
+			Layers []struct {
+				Digest string `json:"digest"`
+			} `json:"layers"`
+		}
+
+		if err = json.Unmarshal(body, &index); err != nil {
+			return
+		}
+
+		if len(index.Layers) > 0 {
+			for _, l := range index.Layers {
+				layers = append(layers, "blobs/"+strings.Replace(l.Digest, ":", "/", 1))
+			}
+
+			return
+		}
+
+		if len(index.Manifests) == 0 {
+			break
+		}
+
+		body, ok = meta["blobs/"+strings.Replace(index.Manifests[0].Digest, ":", "/", 1)]
+	}
+
+	return nil, errInvalidImage
+}
+
+// readLayer reads the files of interest of a (possibly gzipped) layer.
+func readLayer(r io.Reader) (layer imageLayer, err error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) { //nolint:errcheck // short layers are fine
+		if r, err = gzip.NewReader(br); err != nil {
+			return
+		}
+	} else {
+		r = br
+	}
+
+	layer.Files = map[string][]byte{}
+	tr := tar.NewReader(r)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return layer, nil
+		} else if err != nil {
+			return layer, err
+		}
+
+		name := cleanTarPath(hdr.Name)
+		dir, base := path.Split(name)
+
+		switch {
+		case base == ".wh..wh..opq":
+			layer.Opaque = append(layer.Opaque, path.Clean(dir))
+		case strings.HasPrefix(base, ".wh."):
+			layer.Deleted = append(layer.Deleted, dir+strings.TrimPrefix(base, ".wh."))
+		case hdr.Typeflag != tar.TypeReg:
+		case pythonStdlibRe.MatchString(name):
+			layer.Files[name] = nil
+		case slices.Contains(imageFiles, name):
+			if layer.Files[name], err = io.ReadAll(io.LimitReader(tr, maxImageFile)); err != nil {
+				return layer, err
+			}
+		}
+	}
+}
+
+// eachTarEntry calls fn for every entry of the tarball at fname.
+func eachTarEntry(fname string, fn func(name string, hdr *tar.Header, r io.Reader) error) (err error) {
+	f, err := os.Open(fname) //nolint:gosec // user supplied path, on purpose
+	if err != nil {
+		return
+	}
+
+	defer f.Close() //nolint:errcheck // read only
+
+	tr := tar.NewReader(bufio.NewReader(f))
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%s: %w", fname, err)
+		}
+
+		if err = fn(cleanTarPath(hdr.Name), hdr, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func cleanTarPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// deleteTree deletes p, and everything under it, from files.
+func deleteTree(files map[string][]byte, p string) {
+	for name := range files {
+		if name == p || strings.HasPrefix(name, p+"/") {
+			delete(files, name)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// tarFile is a file of a test tarball; a nil content makes a directory.
+type tarFile struct {
+	Name    string
+	Content []byte
+}
+
+func TestClientScanImage(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	docker, oci, bogus := filepath.Join(dir, "docker.tar"), filepath.Join(dir, "oci.tar"), filepath.Join(dir, "bogus.tar")
+	codename := filepath.Join(dir, "codename.tar")
+
+	writeDockerImage(t, docker, "PRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\nID=debian\nVERSION_ID=\"12\"\nVERSION_CODENAME=bookworm\n")
+	writeDockerImage(t, codename, "ID=debian\nVERSION_CODENAME=nosuch\n")
+	writeOCIImage(t, oci)
+	writeFile(t, bogus, makeTar(t, []tarFile{{"etc/os-release", []byte("ID=alpine\n")}}))
+
+	//nolint:govet // ok
+	cases := []struct {
+		args   []string
+		golden string
+		expErr error
+	}{
+		{[]string{"scan", "image", docker, "--as-of", "2025-06-01"}, "image_docker", errEolFound},
+		{[]string{"scan", "image", oci, "--as-of", "2025-06-01", "-f", "json"}, "image_oci_json", errEolFound},
+		{[]string{"scan", "image", codename, "--as-of", "2025-06-01"}, "image_codename", errEolFound},
+		{[]string{"scan", "image", bogus}, "", errInvalidImage},
+		{[]string{"scan", "image", filepath.Join(dir, "missing.tar")}, "", os.ErrNotExist},
+		{[]string{"scan", "image"}, "", errUsage},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.errSink, c.httpClient = buf, &bytes.Buffer{}, &mockHTTPClient{}
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "scan", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected %q, got %q", exp, x)
+			}
+		})
+	}
+}
+
+// writeDockerImage writes a docker save tarball of a Debian image, with
+// the given os-release, whose second layer replaces Python 3.8 with 3.12
+// and adds Go.
+func writeDockerImage(t *testing.T, fname, osRelease string) {
+	t.Helper()
+
+	base := makeTar(t, []tarFile{
+		{"etc/", nil},
+		{"etc/os-release", []byte(osRelease)},
+		{"var/lib/dpkg/status", []byte("Package: libc6\nStatus: install ok installed\nVersion: 2.36-9+deb12u7\n\n" +
+			"Package: nginx\nStatus: install ok installed\nVersion: 1.22.1-9\n\n" +
+			"Package: perl\nStatus: deinstall ok config-files\nVersion: 5.36.0-7\n\n" +
+			"Package: redis\nStatus: install ok installed\nVersion: 5:7.0.15-1~deb12u1\n")},
+		{"usr/local/lib/python3.8/os.py", []byte("# os\n")},
+	})
+	top := makeTar(t, []tarFile{
+		{"usr/local/lib/.wh.python3.8", []byte{}},
+		{"usr/local/lib/python3.12/os.py", []byte("# os\n")},
+		{"usr/local/go/VERSION", []byte("go1.22.5\ntime 2024-06-27T20:11:12Z\n")},
+	})
+
+	writeFile(t, fname, makeTar(t, []tarFile{
+		{"manifest.json", []byte(`[{"Config":"config.json","RepoTags":["app:latest"],"Layers":["base/layer.tar","top/layer.tar"]}]`)},
+		{"config.json", []byte(`{}`)},
+		{"top/layer.tar", top},
+		{"base/layer.tar", base},
+	}))
+}
+
+// writeOCIImage writes an OCI layout tarball of a multi platform Alpine
+// image, with gzipped layers.
+func writeOCIImage(t *testing.T, fname string) {
+	t.Helper()
+
+	layer := gzipped(t, makeTar(t, []tarFile{
+		{"./etc/os-release", []byte("NAME=\"Alpine Linux\"\nID=alpine\nVERSION_ID=3.19.1\n")},
+		{"./lib/apk/db/installed", []byte("C:Q1abc=\nP:musl\nV:1.2.4_git20230717-r4\n\nC:Q1def=\nP:nginx\nV:1.24.0-r7\n")},
+		{"./usr/local/include/node/node_version.h", []byte("#define NODE_MAJOR_VERSION 18\n#define NODE_MINOR_VERSION 20\n#define NODE_PATCH_VERSION 4\n")},
+	}))
+
+	files := []tarFile{{"oci-layout", []byte(`{"imageLayoutVersion":"1.0.0"}`)}}
+	blob := func(content []byte) string {
+		sum := sha256.Sum256(content)
+		digest := hex.EncodeToString(sum[:])
+		files = append(files, tarFile{"blobs/sha256/" + digest, content})
+
+		return "sha256:" + digest
+	}
+
+	manifest := blob([]byte(`{"mediaType":"application/vnd.oci.image.manifest.v1+json","layers":[{"digest":"` + blob(layer) + `"}]}`))
+	index := blob([]byte(`{"mediaType":"application/vnd.oci.image.index.v1+json","manifests":[{"digest":"` + manifest + `"}]}`))
+	files = append(files, tarFile{"index.json", []byte(`{"manifests":[{"digest":"` + index + `"}]}`)})
+
+	writeFile(t, fname, makeTar(t, files))
+}
+
+func makeTar(t *testing.T, files []tarFile) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+
+	for _, f := range files {
+		hdr := &tar.Header{Name: f.Name, Mode: 0o644, Size: int64(len(f.Content)), Typeflag: tar.TypeReg}
+		if f.Content == nil {
+			hdr.Typeflag, hdr.Mode = tar.TypeDir, 0o755
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, err := tw.Write(f.Content); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+
+	if _, err := zw.Write(content); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func writeFile(t *testing.T, fname string, content []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(fname, content, 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// imageRef is a container image found in a manifest.
+type imageRef struct {
+	Image  string
+	File   string
+	Source string
+	Line   int
+}
+
+// removedAPI is a Kubernetes API version that is no longer served after
+// LastServed, for the given kind (or any, if empty).
+type removedAPI struct {
+	APIVersion string
+	Kind       string
+	LastServed string
+}
+
+// removedAPIRef is a removed API version found in a manifest.
+type removedAPIRef struct {
+	removedAPI
+
+	File string
+	Line int
+}
+
+//nolint:gochecknoglobals // ok
+var (
+	// Workload kinds, whose containers' images are scanned.
+	workloadKinds = []string{"Pod", "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "Job", "CronJob"}
+
+	// Removed API versions, most specific (with kind) first.
+	removedAPIs = []removedAPI{
+		{"extensions/v1beta1", "Ingress", "1.21"},
+		{"extensions/v1beta1", "", "1.15"},
+		{"apps/v1beta1", "", "1.15"},
+		{"apps/v1beta2", "", "1.15"},
+		{"networking.k8s.io/v1beta1", "", "1.21"},
+		{"rbac.authorization.k8s.io/v1beta1", "", "1.21"},
+		{"apiextensions.k8s.io/v1beta1", "", "1.21"},
+		{"admissionregistration.k8s.io/v1beta1", "", "1.21"},
+		{"batch/v1beta1", "", "1.24"},
+		{"policy/v1beta1", "", "1.24"},
+		{"discovery.k8s.io/v1beta1", "", "1.24"},
+		{"autoscaling/v2beta1", "", "1.24"},
+		{"autoscaling/v2beta2", "", "1.25"},
+		{"flowcontrol.apiserver.k8s.io/v1beta1", "", "1.25"},
+		{"storage.k8s.io/v1beta1", "CSIStorageCapacity", "1.26"},
+		{"flowcontrol.apiserver.k8s.io/v1beta2", "", "1.28"},
+	}
+
+	imageRe    = regexp.MustCompile(`^\s*(?:-\s+)?image:\s*["']?([^"'\s#]+)`)
+	topLevelRe = regexp.MustCompile(`^(apiVersion|kind):\s*["']?([^"'\s#]+)`)
+	nameRe     = regexp.MustCompile(`^  name:\s*["']?([^"'\s#]+)`)
+	helmRe     = regexp.MustCompile(`^# Source:\s*(\S+)`)
+)
+
+// scanK8s scans the Kubernetes manifests at path (a file, a directory of
+// YAML files or "-" for stdin, i.e. helm template output).
+func (c *client) scanK8s(path string) (findings []finding, err error) {
+	var (
+		images []imageRef
+		apis   []removedAPIRef
+	)
+
+	err = c.readManifests(path, func(file string, content []byte) {
+		imgs, api := scanManifests(file, content)
+		images, apis = append(images, imgs...), append(apis, api...)
+	})
+	if err != nil {
+		return
+	}
+
+	// Removed APIs are warned about, not checked: they say nothing of the
+	// version the cluster actually runs.
+	for _, api := range apis {
+		if c.kubeVersion == "" || compareVersions(c.kubeVersion, api.LastServed) > 0 {
+			c.logf(LogNormal, "scan: %s:%d: %s %s is not served after kubernetes %s",
+				api.File, api.Line, api.APIVersion, api.Kind, api.LastServed)
+		}
+	}
+
+	if findings, err = c.imageFindings(images); err != nil || c.kubeVersion == "" {
+		return
+	}
+
+	return append(findings, finding{
+		Product: "kubernetes", Version: c.kubeVersion, File: "--kube-version", Source: "cluster version",
+	}), nil
+}
+
+// readManifests calls fn for path, or for every YAML file under it.
+func (c *client) readManifests(path string, fn func(file string, content []byte)) (err error) {
+	if path == "-" {
+		content, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return err
+		}
+
+		fn("<stdin>", content)
+
+		return nil
+	}
+
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		if ext := filepath.Ext(p); p != path && ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		content, err := os.ReadFile(p) //nolint:gosec // walking the user supplied tree, on purpose
+		if err != nil {
+			return err
+		}
+
+		fn(filepath.ToSlash(p), content)
+
+		return nil
+	})
+}
+
+// scanManifests returns the images of the workloads in the (multi document)
+// YAML content, along with any removed API versions in use. Only the lines
+// that matter are parsed, not the YAML.
+func scanManifests(file string, content []byte) (images []imageRef, apis []removedAPIRef) {
+	var (
+		apiVersion, kind, name, source string
+		apiLine                        int
+		docImages                      []imageRef
+	)
+
+	endDoc := func() {
+		if slices.Contains(workloadKinds, kind) {
+			for _, img := range docImages {
+				img.Source = kind + "/" + name + " image"
+				if source != "" {
+					img.Source += " (" + source + ")"
+				}
+
+				images = append(images, img)
+			}
+		}
+
+		if api, ok := removedAPIHint(apiVersion, kind); ok {
+			api.Kind = kind
+			apis = append(apis, removedAPIRef{removedAPI: api, File: file, Line: apiLine})
+		}
+
+		apiVersion, kind, name, source, apiLine, docImages = "", "", "", "", 0, nil
+	}
+
+	eachLine(content, func(line string, n int) bool {
+		switch {
+		case strings.HasPrefix(line, "---"):
+			endDoc()
+		case helmRe.MatchString(line):
+			source = helmRe.FindStringSubmatch(line)[1]
+		case topLevelRe.MatchString(line):
+			m := topLevelRe.FindStringSubmatch(line)
+			if m[1] == "kind" {
+				kind = m[2]
+			} else {
+				apiVersion, apiLine = m[2], n
+			}
+		case name == "" && nameRe.MatchString(line):
+			name = nameRe.FindStringSubmatch(line)[1]
+		case imageRe.MatchString(line):
+			docImages = append(docImages, imageRef{Image: imageRe.FindStringSubmatch(line)[1], File: file, Line: n})
+		}
+
+		return true
+	})
+
+	endDoc()
+
+	return
+}
+
+func removedAPIHint(apiVersion, kind string) (api removedAPI, ok bool) {
+	i := slices.IndexFunc(removedAPIs, func(r removedAPI) bool {
+		return r.APIVersion == apiVersion && (r.Kind == "" || r.Kind == kind)
+	})
+	if i < 0 {
+		return
+	}
+
+	return removedAPIs[i], true
+}
+
+// imageFindings maps the images to products, through their pkg:docker purl.
+// Images of no known product, or without a version tag, are skipped.
+func (c *client) imageFindings(images []imageRef) (findings []finding, err error) {
+	if len(images) == 0 {
+		return
+	}
+
+	index, err := c.purlIndex()
+	if err != nil {
+		return
+	}
+
+	for _, img := range images {
+		repo, tag := parseImage(img.Image)
+
+		product := lookupDockerPurl(index, repo)
+		if product == "" {
+			c.logf(LogVerbose, "scan: %s: no product for image %s", img.File, img.Image)
+			continue
+		}
+
+		version := versionRe.FindString(tag)
+		if version == "" {
+			c.logf(LogNormal, "scan: %s:%d: image %s has no version tag", img.File, img.Line, img.Image)
+			continue
+		}
+
+		findings = append(findings, finding{
+			Product: product, Version: version, File: img.File, Line: img.Line, Source: img.Source,
+		})
+	}
+
+	return
+}
+
+// parseImage splits an image reference into repository (with registry, if
+// any) and tag. Digests are dropped.
+func parseImage(ref string) (repo, tag string) {
+	ref, _, _ = strings.Cut(ref, "@")
+
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:]
+	}
+
+	return ref, ""
+}
+
+// lookupDockerPurl finds the product of an image repository. Docker Hub
+// images are looked up without registry, official ones under library/.
+func lookupDockerPurl(index map[string]string, repo string) string {
+	first, rest, hasSlash := strings.Cut(repo, "/")
+	if hasSlash && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		if p := index["pkg:docker/"+strings.ToLower(repo)]; p != "" {
+			return p
+		}
+
+		if first != "docker.io" && first != "index.docker.io" {
+			return index["pkg:docker/"+strings.ToLower(rest)]
+		}
+
+		repo = rest
+	}
+
+	if !strings.Contains(repo, "/") {
+		repo = "library/" + repo
+	}
+
+	return index["pkg:docker/"+strings.ToLower(repo)]
+}
+
+// purlIndex maps the purl identifiers (without version or qualifiers)
+// to their product.
+func (c *client) purlIndex() (index map[string]string, err error) {
+	body, err := c.fetch("/identifiers/purl")
+	if err != nil {
+		return
+	}
+
+	var envelope struct {
+		Result []struct {
+			Identifier string `json:"identifier"`
+			Product    struct {
+				Name string `json:"name"`
+			} `json:"product"`
+		} `json:"result"`
+	}
+
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return
+	}
+
+	index = make(map[string]string, len(envelope.Result))
+	for _, r := range envelope.Result {
+		id, _, _ := strings.Cut(r.Identifier, "?")
+		id, _, _ = strings.Cut(id, "@")
+		index[strings.ToLower(id)] = r.Product.Name
+	}
+
+	return
+}
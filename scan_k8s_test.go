@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClientScanK8s(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join("testdata", "scan", "k8s")
+
+	//nolint:govet // ok
+	cases := []struct {
+		args    []string
+		stdin   string
+		golden  string
+		warning string
+		expErr  error
+	}{
+		{
+			[]string{"scan", "k8s", dir, "--as-of", "2025-06-01"}, "", "k8s_dir",
+			"app.yaml:19: batch/v1beta1 CronJob is not served after kubernetes 1.24", errEolFound,
+		},
+		{
+			[]string{"scan", "k8s", filepath.Join(dir, "app.yaml"), "--kube-version", "1.31", "--as-of", "2025-06-01"}, "", "k8s_kube-version",
+			"app.yaml:19: batch/v1beta1 CronJob is not served after kubernetes 1.24", errEolFound,
+		},
+		{[]string{"scan", "k8s", "-", "--as-of", "2025-06-01"}, "helm.yaml", "k8s_stdin", "<stdin>:17: ", errEolFound},
+		{[]string{"scan", "k8s", "-"}, "apiVersion: batch/v1beta1\nkind: CronJob\n", "k8s_empty", "<stdin>:1: ", nil},
+		{[]string{"scan", "k8s", "-", "--kube-version", "1.24"}, "apiVersion: batch/v1beta1\nkind: CronJob\n", "k8s_served", "", errEolFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf, errBuf := &bytes.Buffer{}, &bytes.Buffer{}
+			c.sink, c.errSink, c.httpClient = buf, errBuf, &mockHTTPClient{}
+			c.stdin = strings.NewReader(tc.stdin)
+
+			if strings.HasSuffix(tc.stdin, ".yaml") {
+				if c.stdin, err = os.Open(filepath.Join(dir, tc.stdin)); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if warning := errBuf.String(); !strings.Contains(warning, tc.warning) || (tc.warning == "") != (warning == "") {
+				t.Fatalf("Expected warning %q, got %q", tc.warning, warning)
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "scan", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected %q, got %q", exp, x)
+			}
+		})
+	}
+}
+
+func TestParseImage(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct{ ref, repo, tag string }{
+		{"node", "node", ""},
+		{"node:18-alpine", "node", "18-alpine"},
+		{"localhost:5000/app", "localhost:5000/app", ""},
+		{"localhost:5000/app:1.2", "localhost:5000/app", "1.2"},
+		{"postgres:13.4@sha256:abc", "postgres", "13.4"},
+	}
+
+	for _, tc := range cases {
+		if repo, tag := parseImage(tc.ref); repo != tc.repo || tag != tc.tag {
+			t.Fatalf("Expected %q to parse as %q %q, got %q %q", tc.ref, tc.repo, tc.tag, repo, tag)
+		}
+	}
+}
+
+func TestLookupDockerPurl(t *testing.T) {
+	t.Parallel()
+
+	index := map[string]string{
+		"pkg:docker/library/node": "nodejs", "pkg:docker/bitnami/redis": "redis", "pkg:docker/quay.io/x/y": "y",
+	}
+
+	for repo, exp := range map[string]string{
+		"node": "nodejs", "docker.io/library/node": "nodejs", "bitnami/redis": "redis",
+		"docker.io/bitnami/redis": "redis", "ghcr.io/bitnami/redis": "redis", "quay.io/x/y": "y", "busybox": "",
+	} {
+		if got := lookupDockerPurl(index, repo); got != exp {
+			t.Fatalf("Expected %q for %q, got %q", exp, repo, got)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// tfRuntime is a resource attribute pinning the version of a managed
+// runtime. Product returns the product given the resource's attributes,
+// for resources (i.e. databases) whose product depends on their engine.
+type tfRuntime struct {
+	Product func(attrs map[string]string) string
+	Attr    string
+}
+
+//nolint:gochecknoglobals // ok
+var (
+	// Managed runtimes, by Terraform resource type.
+	tfRuntimes = map[string][]tfRuntime{
+		"aws_lambda_function":   {{fixedProduct("aws-lambda"), "runtime"}},
+		"aws_db_instance":       {{engineProduct(rdsEngines), "engine_version"}},
+		"aws_rds_cluster":       {{engineProduct(rdsEngines), "engine_version"}},
+		"aws_docdb_cluster":     {{fixedProduct("amazon-documentdb"), "engine_version"}},
+		"aws_msk_cluster":       {{fixedProduct("amazon-msk"), "kafka_version"}},
+		"aws_opensearch_domain": {{enginePrefixProduct(openSearchEngines, "engine_version"), "engine_version"}},
+		"aws_eks_cluster":       {{fixedProduct("amazon-eks"), "version"}},
+		"aws_eks_node_group":    {{fixedProduct("amazon-eks"), "version"}},
+
+		"google_container_cluster":   {{fixedProduct("google-kubernetes-engine"), "min_master_version"}},
+		"google_container_node_pool": {{fixedProduct("google-kubernetes-engine"), "version"}},
+
+		"azurerm_kubernetes_cluster":           {{fixedProduct("azure-kubernetes-service"), "kubernetes_version"}},
+		"azurerm_kubernetes_cluster_node_pool": {{fixedProduct("azure-kubernetes-service"), "orchestrator_version"}},
+	}
+
+	// RDS engines, and their product. Aurora has a lifecycle of its own.
+	rdsEngines = map[string]string{
+		"postgres": "amazon-rds-postgresql", "mysql": "amazon-rds-mysql", "mariadb": "amazon-rds-mariadb",
+	}
+
+	// OpenSearch domain engines, by engine_version prefix (i.e. OpenSearch_2.11).
+	openSearchEngines = map[string]string{"OpenSearch": "opensearch", "Elasticsearch": "elasticsearch"}
+
+	// Products whose release names are the attribute values themselves.
+	tfVerbatim = []string{"aws-lambda"}
+
+	tfResourceRe = regexp.MustCompile(`^\s*resource\s+"([^"]+)"\s+"([^"]+)"`)
+	tfAttrRe     = regexp.MustCompile(`^\s*(\w+)\s*=\s*"([^"]*)"`)
+	tfStringRe   = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+)
+
+func fixedProduct(product string) func(map[string]string) string {
+	return func(map[string]string) string { return product }
+}
+
+func engineProduct(engines map[string]string) func(map[string]string) string {
+	return func(attrs map[string]string) string { return engines[attrs["engine"]] }
+}
+
+func enginePrefixProduct(engines map[string]string, attr string) func(map[string]string) string {
+	return func(attrs map[string]string) string {
+		engine, _, _ := strings.Cut(attrs[attr], "_")
+		return engines[engine]
+	}
+}
+
+// scanTerraform walks root for .tf files and reports the managed runtimes
+// pinned by their resources.
+func (c *client) scanTerraform(root string) (findings []finding, err error) {
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if filepath.Ext(path) != ".tf" {
+			return nil
+		}
+
+		content, err := os.ReadFile(path) //nolint:gosec // walking the user supplied tree, on purpose
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(root, path) //nolint:errcheck // path is within root
+
+		for _, f := range c.scanHCL(content) {
+			f.File = filepath.ToSlash(rel)
+			findings = append(findings, f)
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// scanHCL reports the runtimes pinned by the resources in content. It only
+// looks at the literal string attributes of the resources it knows of, one
+// per line, which is how they are written in practice.
+func (c *client) scanHCL(content []byte) (findings []finding) {
+	var (
+		resType, resName string
+		attrs            map[string]string
+		lines            map[string]int
+		depth            int
+	)
+
+	eachLine(content, func(line string, n int) bool {
+		code := tfStringRe.ReplaceAllString(line, `""`)
+		code, _, _ = strings.Cut(code, "#")
+		code, _, _ = strings.Cut(code, "//")
+
+		if depth == 0 {
+			if m := tfResourceRe.FindStringSubmatch(line); m != nil {
+				resType, resName, attrs, lines = m[1], m[2], map[string]string{}, map[string]int{}
+			}
+		} else if m := tfAttrRe.FindStringSubmatch(line); depth == 1 && m != nil && attrs != nil {
+			attrs[m[1]], lines[m[1]] = m[2], n
+		}
+
+		depth += strings.Count(code, "{") - strings.Count(code, "}")
+		if depth > 0 || attrs == nil {
+			return true
+		}
+
+		for _, rt := range tfRuntimes[resType] {
+			value, ok := attrs[rt.Attr]
+			product := rt.Product(attrs)
+
+			switch {
+			case !ok || product == "":
+				continue
+			case strings.Contains(value, "${"):
+				value = ""
+			case !slices.Contains(tfVerbatim, product):
+				value = versionRe.FindString(value)
+			}
+
+			if value == "" {
+				c.logf(LogVerbose, "scan: %s.%s: no version in %s", resType, resName, rt.Attr)
+				continue
+			}
+
+			findings = append(findings, finding{
+				Product: product, Version: value, Line: lines[rt.Attr], Source: resType + "." + resName + " " + rt.Attr,
+			})
+		}
+
+		attrs = nil
+
+		return true
+	})
+
+	return
+}
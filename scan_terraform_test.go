@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestClientScanTerraform(t *testing.T) {
+	t.Parallel()
+
+	c, err := newClient([]string{"scan", "terraform", filepath.Join("testdata", "scan", "terraform"), "--as-of", "2025-06-01"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	c.sink, c.httpClient = buf, &mockHTTPClient{}
+
+	if err = c.handle(); !errors.Is(err, errEolFound) {
+		t.Fatalf("Expected error %v, got %v", errEolFound, err)
+	}
+
+	exp, err := os.ReadFile(filepath.Join("testdata", "scan", "terraform_dir"))
+	if err != nil {
+		t.Fatalf("Failed to read golden copy: %v", err)
+	}
+
+	if x := buf.String(); x != string(exp) {
+		t.Fatalf("Expected %q, got %q", exp, x)
+	}
+}
+
+func TestScanHCL(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		hcl string
+		exp []finding
+	}{
+		{"resource \"aws_eks_cluster\" \"x\" {\n  version = \"1.30\"\n}", []finding{
+			{Product: "amazon-eks", Version: "1.30", Line: 2, Source: "aws_eks_cluster.x version"},
+		}},
+		{"resource \"aws_db_instance\" \"x\" {\n  engine = \"oracle-ee\"\n  engine_version = \"19\"\n}", nil},
+		{"resource \"aws_lambda_function\" \"x\" {\n  # runtime = \"go1.x\"\n  tags = { runtime = \"nodejs16.x\" }\n}", nil},
+		{"data \"aws_eks_cluster\" \"x\" {\n  version = \"1.20\"\n}", nil},
+		{"resource \"aws_opensearch_domain\" \"x\" {\n  engine_version = \"Elasticsearch_7.10\"\n}", []finding{
+			{Product: "elasticsearch", Version: "7.10", Line: 2, Source: "aws_opensearch_domain.x engine_version"},
+		}},
+		{"resource \"aws_opensearch_domain\" \"x\" {\n  engine_version = \"OpenSearch_2.11\"\n}", []finding{
+			{Product: "opensearch", Version: "2.11", Line: 2, Source: "aws_opensearch_domain.x engine_version"},
+		}},
+	}
+
+	c := &client{}
+
+	for _, tc := range cases {
+		if got := c.scanHCL([]byte(tc.hcl)); !reflect.DeepEqual(got, tc.exp) {
+			t.Fatalf("Expected %v, got %v", tc.exp, got)
+		}
+	}
+}
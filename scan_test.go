@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestClientScan(t *testing.T) {
+	t.Parallel()
+
+	repo := filepath.Join("testdata", "scan", "repo")
+
+	//nolint:govet // ok
+	cases := []struct {
+		args   []string
+		golden string
+		expErr error
+	}{
+		{[]string{"scan", "dir", repo, "--as-of", "2025-06-01"}, "dir", errEolFound},
+		{[]string{"scan", "dir", repo, "--as-of", "2025-06-01", "-f", "json"}, "dir_json", errEolFound},
+		{[]string{"scan", "dir", filepath.Join(repo, "web"), "--as-of", "2025-06-01"}, "dir_web", nil},
+		{[]string{"scan", "dir", filepath.Join(repo, "missing")}, "", os.ErrNotExist},
+		{[]string{"scan", "bogus"}, "", errUsage},
+		{[]string{"scan"}, "", errUsage},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(tc.args)
+			if errors.Is(err, tc.expErr) && err != nil {
+				return
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.httpClient = buf, &mockHTTPClient{}
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "scan", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := buf.String(); x != string(exp) {
+				t.Fatalf("Expected %q, got %q", exp, x)
+			}
+		})
+	}
+}
+
+func TestDetectors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		file, content string
+		exp           []finding
+	}{
+		{"go.mod", "module x\n\ngo 1.23.1\n", []finding{{Product: "go", Version: "1.23", Source: "go directive", Line: 3}}},
+		{".nvmrc", "lts/hydrogen\n", nil},
+		{".nvmrc", "\n# pinned\nv20\n", []finding{{Product: "nodejs", Version: "20", Source: "version file", Line: 3}}},
+		{"package.json", `{"dependencies": {"node": "1.0.0"}}`, nil},
+		{"pyproject.toml", "requires-python = '~=3.10'\n", []finding{{Product: "python", Version: "3.10", Source: "requires-python", Line: 1}}},
+		{".tool-versions", "java corretto-21.0.1.12.1\nshellcheck 0.9.0\n", []finding{{Product: "amazon-corretto", Version: "21.0.1.12.1", Source: "asdf java", Line: 1}}},
+		{"pom.xml", "<java.version>1.8</java.version>\n<release>17</release>", []finding{{Product: "eclipse-temurin", Version: "8", Source: "Java release", Line: 1}}},
+		{"global.json", `{"msbuild-sdks": {}}`, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.file, func(t *testing.T) {
+			t.Parallel()
+
+			if got := fileDetectors[tc.file]([]byte(tc.content)); !reflect.DeepEqual(got, tc.exp) {
+				t.Fatalf("Expected %v, got %v", tc.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// productSummary is a product, as listed by the /products endpoint.
+type productSummary struct {
+	Name     string   `json:"name"`
+	Label    string   `json:"label"`
+	Category string   `json:"category"`
+	URI      string   `json:"uri"`
+	Aliases  []string `json:"aliases"`
+	Tags     []string `json:"tags"`
+}
+
+// Maximum number of "did you mean" suggestions.
+const maxSuggestions = 3
+
+// Search match scores, from best to worst.
+const (
+	scoreName = 100 - iota*5
+	scoreAlias
+	scoreLabel
+	scoreNamePrefix
+	scoreAliasPrefix
+	scoreLabelPrefix
+	scoreNameContains
+	scoreAliasContains
+	scoreLabelContains
+	scoreTag
+	scoreCategory
+	scoreFuzzy
+)
+
+func (c *client) search(query string) (err error) {
+	products, err := c.fetchProducts()
+	if err != nil {
+		return
+	}
+
+	matches := rankProducts(products, query)
+	c.response, err = json.Marshal(map[string]any{"total": len(matches), "result": matches})
+
+	return
+}
+
+func (c *client) fetchProducts() (products []productSummary, err error) {
+	body, err := c.fetch("/products")
+	if err != nil {
+		return
+	}
+
+	var envelope struct {
+		Result []productSummary `json:"result"`
+	}
+
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return
+	}
+
+	return envelope.Result, nil
+}
+
+// withProduct runs fn for product name pn. If that fails with "not found" it
+// looks pn up in the products list: aliases are transparently resolved to
+// the canonical product name (and fn retried), while unknown names get
+// a [ProductNotFoundError] with the closest matching names as suggestions.
+func (c *client) withProduct(pn string, fn func(pn string) error) (err error) {
+	if err = fn(pn); !errors.Is(err, errNotFound) && !errors.Is(err, errReleaseNotFound) {
+		return
+	}
+
+	products, lerr := c.fetchProducts()
+	if lerr != nil {
+		return
+	}
+
+	for _, p := range products {
+		switch {
+		case p.Name == pn:
+			return // The product exists, so the original error stands.
+		case slices.Contains(p.Aliases, pn):
+			c.logf(LogVerbose, "product %s: resolved alias to %s", pn, p.Name)
+
+			return fn(p.Name)
+		}
+	}
+
+	var suggestions []string
+	for _, p := range rankProducts(products, pn) {
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+
+		suggestions = append(suggestions, p.Name)
+	}
+
+	return &ProductNotFoundError{Product: pn, Suggestions: suggestions}
+}
+
+// rankProducts returns the products matching query by name, label, alias,
+// tag or category, best matches first.
+func rankProducts(products []productSummary, query string) (matches []productSummary) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return
+	}
+
+	scores := map[string]int{}
+
+	for _, p := range products {
+		if score := matchScore(p, query); score > 0 {
+			scores[p.Name] = score
+			matches = append(matches, p)
+		}
+	}
+
+	slices.SortStableFunc(matches, func(a, b productSummary) int {
+		return cmp.Or(cmp.Compare(scores[b.Name], scores[a.Name]), cmp.Compare(a.Name, b.Name))
+	})
+
+	return
+}
+
+//nolint:gocyclo,cyclop // it's a flat list of rules
+func matchScore(p productSummary, query string) (score int) {
+	name, label := strings.ToLower(p.Name), strings.ToLower(p.Label)
+	aliases := make([]string, len(p.Aliases))
+
+	for i, a := range p.Aliases {
+		aliases[i] = strings.ToLower(a)
+	}
+
+	anyAlias := func(fn func(string, string) bool) bool {
+		return slices.ContainsFunc(aliases, func(a string) bool { return fn(a, query) })
+	}
+
+	equal := func(a, b string) bool { return a == b }
+
+	switch {
+	case name == query:
+		return scoreName
+	case anyAlias(equal):
+		return scoreAlias
+	case label == query:
+		return scoreLabel
+	case strings.HasPrefix(name, query):
+		return scoreNamePrefix
+	case anyAlias(strings.HasPrefix):
+		return scoreAliasPrefix
+	case strings.HasPrefix(label, query):
+		return scoreLabelPrefix
+	case strings.Contains(name, query):
+		return scoreNameContains
+	case anyAlias(strings.Contains):
+		return scoreAliasContains
+	case strings.Contains(label, query):
+		return scoreLabelContains
+	case slices.Contains(p.Tags, query):
+		return scoreTag
+	case p.Category == query:
+		return scoreCategory
+	}
+
+	maxDist := 1
+	if len(query) > 4 { //nolint:mnd // short names tolerate a single typo
+		maxDist = 2
+	}
+
+	for _, candidate := range append([]string{name}, aliases...) {
+		if d := levenshtein(candidate, query); d <= maxDist {
+			score = max(score, scoreFuzzy-d)
+		}
+	}
+
+	return
+}
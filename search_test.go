@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRankProducts(t *testing.T) {
+	t.Parallel()
+
+	products := []productSummary{
+		{Name: "go", Label: "Go", Category: "lang", Aliases: []string{"golang"}, Tags: []string{"google", "lang"}},
+		{Name: "godot", Label: "Godot", Category: "app", Tags: []string{"game-engine"}},
+		{Name: "ubuntu", Label: "Ubuntu", Category: "os", Tags: []string{"canonical", "linux-distribution"}},
+		{Name: "nodejs", Label: "Node.js", Category: "framework", Aliases: []string{"node"}, Tags: []string{"js"}},
+		{Name: "kubernetes", Label: "Kubernetes", Category: "server-app", Aliases: []string{"k8s"}},
+	}
+
+	tests := []struct {
+		query string
+		exp   []string
+	}{
+		{"", nil},
+		{"go", []string{"go", "godot"}},
+		{"GOLANG", []string{"go"}},
+		{"node", []string{"nodejs"}},
+		{"node.js", []string{"nodejs"}},
+		{"canonical", []string{"ubuntu"}},
+		{"os", []string{"ubuntu"}},
+		{"ubunut", []string{"ubuntu"}},
+		{"k8", []string{"kubernetes"}},
+		{"nothing-like-it", nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, p := range rankProducts(products, tc.query) {
+				got = append(got, p.Name)
+			}
+
+			if !slices.Equal(got, tc.exp) {
+				t.Fatalf("expected %v, got %v", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestClientWithProductSuggestions(t *testing.T) {
+	t.Parallel()
+
+	c, err := newClient([]string{"product", "ubunt"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	c.httpClient = &mockHTTPClient{}
+
+	err = c.handle()
+
+	var pErr *ProductNotFoundError
+	if !errors.As(err, &pErr) {
+		t.Fatalf("Expected ProductNotFoundError, got %v", err)
+	}
+
+	if len(pErr.Suggestions) == 0 || pErr.Suggestions[0] != "ubuntu" {
+		t.Fatalf("Expected ubuntu to be the first suggestion, got %v", pErr.Suggestions)
+	}
+
+	if !strings.Contains(err.Error(), "did you mean ubuntu") {
+		t.Fatalf("Expected a suggestion in %q", err.Error())
+	}
+}
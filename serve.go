@@ -0,0 +1,343 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// cachingClient is an httpClient caching the successful GET responses of
+// the wrapped one, for up to ttl. As clients choose the paths (thus the
+// keys), it holds at most size entries, evicting the oldest.
+type cachingClient struct {
+	next    httpClient
+	entries map[string]cachedResponse
+	ttl     time.Duration
+	size    int
+	mu      sync.Mutex
+}
+
+type cachedResponse struct {
+	at   time.Time
+	body []byte
+}
+
+// checkRequest is the body of POST /check, shaped like an inventory.
+type checkRequest struct {
+	Entries []inventoryEntry `json:"entries"`
+}
+
+// purlLookup is the response of GET /lookup. The evaluation is only there
+// when the purl has a version.
+type purlLookup struct {
+	*evaluation
+
+	Purl    string `json:"purl"`
+	Product string `json:"product"`
+	Version string `json:"version,omitempty"`
+}
+
+// Server defaults and limits.
+const (
+	DefaultListen     = "localhost:8080"
+	DefaultCacheTTL   = "1h"
+	maxCheckBody      = 1 << 20
+	maxCheckEntries   = 1000
+	maxCacheEntries   = 1000
+	shutdownTimeout   = 10 * time.Second
+	readHeaderTimeout = 10 * time.Second
+)
+
+// API v1 endpoints proxied by serve-api, by their first path segment.
+//
+//nolint:gochecknoglobals // ok
+var proxiedEndpoints = []string{"", "products", "categories", "tags", "identifiers"}
+
+func (cc *cachingClient) Do(req *http.Request) (resp *http.Response, err error) {
+	if req.Method != http.MethodGet {
+		return cc.next.Do(req)
+	}
+
+	key := req.URL.String()
+
+	cc.mu.Lock()
+	e, ok := cc.entries[key]
+
+	if ok && time.Since(e.at) > cc.ttl {
+		delete(cc.entries, key)
+
+		ok = false
+	}
+	cc.mu.Unlock()
+
+	if !ok {
+		if resp, err = cc.next.Do(req); err != nil || resp.StatusCode != http.StatusOK {
+			return
+		}
+
+		defer resp.Body.Close() //nolint:errcheck // ok
+
+		e = cachedResponse{at: time.Now()}
+		if e.body, err = io.ReadAll(resp.Body); err != nil {
+			return nil, err
+		}
+
+		cc.store(key, e)
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK, Status: "200 OK", Header: http.Header{"Content-Type": {"application/json"}},
+		Body: io.NopCloser(bytes.NewReader(e.body)), ContentLength: int64(len(e.body)), Request: req,
+	}, nil
+}
+
+// store caches e under key. When full, it first drops the expired entries
+// and then, if need be, the oldest one.
+func (cc *cachingClient) store(key string, e cachedResponse) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if _, ok := cc.entries[key]; !ok && len(cc.entries) >= cc.size {
+		var oldest string
+
+		for k, x := range cc.entries {
+			if time.Since(x.at) > cc.ttl {
+				delete(cc.entries, k)
+			} else if oldest == "" || x.at.Before(cc.entries[oldest].at) {
+				oldest = k
+			}
+		}
+
+		if len(cc.entries) >= cc.size {
+			delete(cc.entries, oldest)
+		}
+	}
+
+	cc.entries[key] = e
+}
+
+// serveAPI serves the API handler on --listen until interrupted, then
+// shuts down gracefully, letting the requests in flight complete.
+func (c *client) serveAPI() (err error) {
+	ttl, err := parseExtendedDuration(cmp.Or(c.cacheTTL, DefaultCacheTTL))
+	if err != nil {
+		return fmt.Errorf("%w: invalid --cache-ttl: %w", errUsage, err)
+	}
+
+	c.httpClient = &cachingClient{next: c.httpClient, ttl: ttl, size: maxCacheEntries, entries: map[string]cachedResponse{}}
+
+	ln, err := net.Listen("tcp", cmp.Or(c.listen, DefaultListen))
+	if err != nil {
+		return
+	}
+
+	srv := &http.Server{Handler: c.apiHandler(), ReadHeaderTimeout: readHeaderTimeout}
+	done := make(chan error, 1)
+
+	go func() { done <- srv.Serve(ln) }()
+
+	c.logf(LogNormal, "serve-api: listening on http://%s", ln.Addr())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err = <-done:
+		return
+	case <-ctx.Done():
+	}
+
+	c.logf(LogNormal, "serve-api: shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return srv.Shutdown(ctx)
+}
+
+// apiHandler routes the API: the proxied v1 endpoints, the aggregations
+// (check, upcoming, lookup) and the health checks.
+func (c *client) apiHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/{path...}", c.serveProxy)
+	mux.HandleFunc("POST /check", c.serveCheck)
+	mux.HandleFunc("GET /upcoming", c.serveUpcoming)
+	mux.HandleFunc("GET /lookup", c.serveLookup)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET /readyz", c.serveReady)
+
+	return mux
+}
+
+// requestClient returns a copy of the client for serving one request, as
+// commands keep their state (response, flags) in it.
+func (c *client) requestClient() *client {
+	rc := *c
+	rc.response, rc.deferredErr, rc.args, rc.product = nil, nil, nil, ""
+
+	return &rc
+}
+
+func (c *client) serveProxy(w http.ResponseWriter, r *http.Request) {
+	p := strings.Trim(r.PathValue("path"), "/")
+	if first, _, _ := strings.Cut(p, "/"); !slices.Contains(proxiedEndpoints, first) {
+		writeError(w, fmt.Errorf("endpoint /%s %w", p, errNotFound))
+		return
+	}
+
+	body, err := c.requestClient().fetch("/" + p)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body) //nolint:errcheck,gosec // best effort
+}
+
+func (c *client) serveCheck(w http.ResponseWriter, r *http.Request) {
+	var req checkRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxCheckBody)).Decode(&req); err != nil {
+		writeError(w, fmt.Errorf("%w: invalid check request: %w", errUsage, err))
+		return
+	} else if len(req.Entries) > maxCheckEntries {
+		writeError(w, fmt.Errorf("%w: at most %d entries can be checked at once", errUsage, maxCheckEntries))
+		return
+	}
+
+	rc := c.requestClient()
+	results := []inventoryResult{}
+
+	var failed int
+
+	for _, e := range req.Entries {
+		ev, err := rc.evaluate(e.Product, e.Version)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if ev.Status == StatusEol || ev.Status == StatusUnknownProduct || ev.Status == StatusUnknownRelease {
+			failed++
+		}
+
+		results = append(results, inventoryResult{inventoryEntry: e, evaluation: ev})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"total": len(results), "failed": failed, "result": results})
+}
+
+func (c *client) serveUpcoming(w http.ResponseWriter, r *http.Request) {
+	q, rc := r.URL.Query(), c.requestClient()
+	rc.within, rc.from, rc.to, rc.category, rc.tag = q.Get("within"), q.Get("from"), q.Get("to"), q.Get("category"), q.Get("tag")
+
+	if err := rc.upcoming(); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(rc.response) //nolint:errcheck,gosec // best effort
+}
+
+// serveLookup maps a purl (pkg:npm/react@18.2.0) to its product and, given
+// a version, evaluates it.
+func (c *client) serveLookup(w http.ResponseWriter, r *http.Request) {
+	purl := r.URL.Query().Get("purl")
+	if !strings.HasPrefix(purl, "pkg:") {
+		writeError(w, fmt.Errorf("%w: lookup requires a purl (pkg:type/name[@version])", errUsage))
+		return
+	}
+
+	id, version := splitPurl(purl)
+	rc := c.requestClient()
+
+	index, err := rc.purlIndex()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	res := purlLookup{Purl: id, Product: lookupPurl(index, id), Version: version}
+	if res.Product == "" {
+		writeError(w, fmt.Errorf("product for %s %w", id, errNotFound))
+		return
+	}
+
+	if version != "" {
+		ev, err := rc.evaluate(res.Product, version)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		res.evaluation = &ev
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"result": res})
+}
+
+func (c *client) serveReady(w http.ResponseWriter, _ *http.Request) {
+	if _, err := c.requestClient().fetch("/"); err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable", "error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// splitPurl splits a purl into its identifier (without qualifiers, with the
+// npm scope's @ encoded, as in the identifiers index) and version.
+func splitPurl(purl string) (id, version string) {
+	id, _, _ = strings.Cut(purl, "#")
+	id, _, _ = strings.Cut(id, "?")
+
+	if i := strings.LastIndex(id, "@"); i > strings.LastIndex(id, "/") {
+		id, version = id[:i], id[i+1:]
+	}
+
+	return strings.ReplaceAll(id, "/@", "/%40"), version
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) //nolint:errcheck,errchkjson,gosec // best effort
+}
+
+// writeError responds with the JSON error object of err (see errorJSON) and
+// the HTTP status matching its failure class.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	var apiErr *APIError
+
+	switch {
+	case errors.Is(err, errUsage):
+		status = http.StatusBadRequest
+	case errors.Is(err, errNotFound), errors.Is(err, errReleaseNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, errNetwork), errors.As(err, &apiErr):
+		status = http.StatusBadGateway
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(errorJSON(err)) //nolint:errcheck,gosec // best effort
+}
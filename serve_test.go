@@ -0,0 +1,153 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingHTTPClient counts the requests reaching the mock.
+type countingHTTPClient struct {
+	mockHTTPClient
+
+	n atomic.Int32
+}
+
+func (m *countingHTTPClient) Do(r *http.Request) (*http.Response, error) {
+	m.n.Add(1)
+	return m.mockHTTPClient.Do(r)
+}
+
+func TestClientAPIHandler(t *testing.T) {
+	t.Parallel()
+
+	c, err := newClient([]string{"serve-api", "--as-of", "2025-06-01"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	c.httpClient, c.errSink = &mockHTTPClient{}, io.Discard
+
+	srv := httptest.NewServer(c.apiHandler())
+	t.Cleanup(srv.Close)
+
+	cases := []struct {
+		method, path, body string
+		golden             string
+		status             int
+	}{
+		{"GET", "/v1/products/go", "", "../golden/api_v1_products_go", http.StatusOK},
+		{"GET", "/v1/products/golng", "", "", http.StatusNotFound},
+		{"GET", "/v1/bogus", "", "", http.StatusNotFound},
+		{"POST", "/check", `{"entries":[{"product":"go","version":"1.22"},{"product":"golang","version":"1.24"},{"product":"golng","version":"1"}]}`, "check", http.StatusOK},
+		{"POST", "/check", `{"entries":`, "", http.StatusBadRequest},
+		{"GET", "/upcoming?within=30d&category=lang", "", "upcoming", http.StatusOK},
+		{"GET", "/upcoming?within=soon", "", "", http.StatusBadRequest},
+		{"GET", "/lookup?purl=pkg:npm/react@17.0.2", "", "lookup", http.StatusOK},
+		{"GET", "/lookup?purl=pkg:npm/%40angular/core", "", "lookup_no_version", http.StatusOK},
+		{"GET", "/lookup?purl=pkg:npm/left-pad@1.3.0", "", "", http.StatusNotFound},
+		{"GET", "/lookup", "", "", http.StatusBadRequest},
+		{"GET", "/healthz", "", "healthz", http.StatusOK},
+		{"GET", "/readyz", "", "readyz", http.StatusOK},
+		{"DELETE", "/check", "", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.method+tc.path, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(t.Context(), tc.method, srv.URL+tc.path, strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.status {
+				t.Fatalf("Expected status %d, got %d", tc.status, resp.StatusCode)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "serve", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if x := string(body); x != string(exp) {
+				t.Fatalf("Expected %q, got %q", exp, x)
+			}
+		})
+	}
+}
+
+func TestCachingClient(t *testing.T) {
+	t.Parallel()
+
+	next := &countingHTTPClient{}
+	cc := &cachingClient{next: next, ttl: time.Hour, size: 2, entries: map[string]cachedResponse{}}
+
+	get := func(path string) int {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "https://endoflife.date/api/v1"+path, http.NoBody)
+
+		resp, err := cc.Do(req)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	get("/products/go")
+	get("/products/go")
+
+	if n := next.n.Load(); n != 1 {
+		t.Fatalf("Expected 1 request, got %d", n)
+	}
+
+	// Errors aren't cached.
+	get("/products/golng")
+
+	if status := get("/products/golng"); status != http.StatusNotFound || next.n.Load() != 3 {
+		t.Fatalf("Expected uncached 404, got %d after %d requests", status, next.n.Load())
+	}
+
+	cc.ttl = 0
+	get("/products/go")
+
+	if n := next.n.Load(); n != 4 {
+		t.Fatalf("Expected expired entry to be fetched again, got %d requests", n)
+	}
+
+	// When full, the oldest entry is evicted.
+	cc.ttl = time.Hour
+	for _, p := range []string{"/products/python", "/products/nodejs"} {
+		get(p)
+	}
+
+	if _, ok := cc.entries["https://endoflife.date/api/v1/products/go"]; ok || len(cc.entries) != 2 {
+		t.Fatalf("Expected the oldest entry evicted, got %d entries", len(cc.entries))
+	}
+}
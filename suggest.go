@@ -0,0 +1,202 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// upgrade is a release suggested to upgrade to.
+type upgrade struct {
+	Release     string `json:"release"`
+	Latest      string `json:"latest,omitempty"`
+	EolFrom     string `json:"eolFrom,omitempty"`
+	Link        string `json:"link,omitempty"`
+	SupportDays *int   `json:"supportDays,omitempty"` // Until EOL, if known.
+	IsLts       bool   `json:"isLts"`
+}
+
+// suggestion is the response of the suggest command.
+type suggestion struct {
+	Product string       `json:"product"`
+	Version string       `json:"version"`
+	Release string       `json:"release"`
+	Status  string       `json:"status"`
+	EolFrom string       `json:"eolFrom,omitempty"`
+	Next    *upgrade     `json:"next"`
+	Lts     *upgrade     `json:"lts"`
+	Newest  *upgrade     `json:"newest"`
+	Changes []fileChange `json:"changes,omitempty"`
+}
+
+// fileChange is a line rewritten by suggest --apply.
+type fileChange struct {
+	File string `json:"file"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+	Line int    `json:"line"`
+}
+
+//nolint:gochecknoglobals // ok
+var (
+	// Files holding nothing but a version, which --apply can rewrite.
+	versionFiles = []string{".nvmrc", ".node-version", ".python-version", ".ruby-version", ".terraform-version"}
+
+	goDirectiveRe = regexp.MustCompile(`^(go\s+)(\S+)`)
+)
+
+// suggest recommends the releases to upgrade product pn's version to: the
+// next maintained release, the newest LTS one and the newest one. With
+// --apply, it rewrites the version pinned by a file to the next release.
+func (c *client) suggest(pn, version string) (err error) {
+	rel, envelope, err := c.lookupRelease(pn, version)
+	if err != nil {
+		return
+	}
+
+	product, _ := envelope["result"].(map[string]any) //nolint:errcheck // ok
+	releases := toSlice(product["releases"])
+	now := c.now()
+
+	s := suggestion{
+		Product: pn, Version: version, Release: getString(rel, "name"), Status: releaseStatus(rel), EolFrom: getString(rel, "eolFrom"),
+	}
+
+	// Releases are listed newest first.
+	current := slices.IndexFunc(releases, func(r any) bool {
+		m, _ := r.(map[string]any) //nolint:errcheck // ok
+		return getString(m, "name") == s.Release
+	})
+
+	for i, r := range releases {
+		r, _ := r.(map[string]any) //nolint:errcheck // ok
+		if i == 0 {
+			s.Newest = newUpgrade(r, now)
+		}
+
+		if lts, _ := r["isLts"].(bool); lts && s.Lts == nil { //nolint:errcheck // ok
+			s.Lts = newUpgrade(r, now)
+		}
+
+		if maintained, _ := r["isMaintained"].(bool); maintained && i < current { //nolint:errcheck // ok
+			s.Next = newUpgrade(r, now) // The oldest one newer than current wins.
+		}
+	}
+
+	if c.apply != "" {
+		if s.Changes, err = c.applyUpgrade(c.apply, pn, releases, s); err != nil {
+			return
+		}
+	}
+
+	c.response, err = json.Marshal(map[string]any{"result": s})
+
+	return
+}
+
+func newUpgrade(rel map[string]any, now time.Time) *upgrade {
+	latest, _ := rel["latest"].(map[string]any) //nolint:errcheck // ok
+	u := &upgrade{
+		Release: getString(rel, "name"), Latest: getString(latest, "name"), EolFrom: getString(rel, "eolFrom"),
+		Link: getString(latest, "link"),
+	}
+
+	u.IsLts, _ = rel["isLts"].(bool) //nolint:errcheck // ok
+	u.SupportDays = daysUntil(now, u.EolFrom)
+
+	return u
+}
+
+// applyUpgrade rewrites the versions of product pn's current release that
+// fname pins to the next maintained release (keeping their precision, i.e.
+// 18 becomes 20 while 18.17.0 becomes 20.19.2), unless --dry-run.
+func (c *client) applyUpgrade(fname, pn string, releases []any, s suggestion) (changes []fileChange, err error) {
+	name := filepath.Base(fname)
+	if name != "go.mod" && !slices.Contains(versionFiles, name) && !dockerfileRe.MatchString(name) {
+		return nil, fmt.Errorf("%w: --apply supports go.mod, version files (%s) and Dockerfiles",
+			errUsage, strings.Join(versionFiles, ", "))
+	}
+
+	if s.Next == nil {
+		c.logf(LogNormal, "suggest: %s %s is the newest maintained release, nothing to apply", pn, s.Release)
+		return
+	}
+
+	content, err := os.ReadFile(fname) //nolint:gosec // user supplied path, on purpose
+	if err != nil {
+		return
+	}
+
+	findings, err := c.detectFile(fname, content)
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(string(content), "\n")
+	target := cmp.Or(s.Next.Latest, s.Next.Release)
+
+	for _, f := range findings {
+		if rel, _, _ := matchRelease(releases, f.Version); f.Product != pn || getString(rel, "name") != s.Release {
+			continue
+		}
+
+		old := lines[f.Line-1]
+		if lines[f.Line-1] = rewriteVersion(name, old, target); lines[f.Line-1] != old {
+			changes = append(changes, fileChange{File: filepath.ToSlash(fname), Line: f.Line, Old: old, New: lines[f.Line-1]})
+		}
+	}
+
+	if len(changes) == 0 || c.dryRun {
+		return
+	}
+
+	fi, err := os.Stat(fname)
+	if err != nil {
+		return
+	}
+
+	return changes, os.WriteFile(fname, []byte(strings.Join(lines, "\n")), fi.Mode().Perm())
+}
+
+// rewriteVersion replaces the version in a go.mod go directive, version file
+// line or Dockerfile FROM line with target, truncated to the same precision.
+func rewriteVersion(name, line, target string) string {
+	replace := func(s string) string {
+		v := versionRe.FindStringIndex(s)
+		if v == nil {
+			return s
+		}
+
+		segments := strings.Count(s[v[0]:v[1]], ".") + 1
+		parts := strings.Split(target, ".")
+
+		return s[:v[0]] + strings.Join(parts[:min(segments, len(parts))], ".") + s[v[1]:]
+	}
+
+	switch {
+	case name == "go.mod":
+		if m := goDirectiveRe.FindStringSubmatchIndex(line); m != nil {
+			return line[:m[4]] + replace(line[m[4]:m[5]]) + line[m[5]:]
+		}
+	case dockerfileRe.MatchString(name):
+		if m := dockerFromRe.FindStringSubmatchIndex(line); m != nil {
+			// A digest pins the old version, whatever the tag says.
+			image := line[m[2]:m[3]]
+			if repo, tag := parseImage(image); tag != "" && !strings.Contains(image, "@") {
+				image = repo + ":" + replace(tag)
+			}
+
+			return line[:m[2]] + image + line[m[3]:]
+		}
+	default:
+		return replace(line)
+	}
+
+	return line
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClientSuggest(t *testing.T) {
+	t.Parallel()
+
+	//nolint:govet // ok
+	cases := []struct {
+		args     []string
+		file     string // Copied to a temporary directory, for --apply.
+		golden   string
+		expErr   error
+		expWrite bool
+	}{
+		{[]string{"suggest", "nodejs", "18"}, "", "nodejs", nil, false},
+		{[]string{"suggest", "golang", "1.22", "-f", "json"}, "", "go_json", nil, false},
+		{[]string{"suggest", "nodejs", "18", "--apply"}, ".nvmrc", "apply_nvmrc", nil, true},
+		{[]string{"suggest", "go", "1.22", "--apply"}, "go.mod", "apply_gomod", nil, true},
+		{[]string{"suggest", "nodejs", "18.17", "--dry-run", "--apply"}, "Dockerfile", "apply_dockerfile", nil, false},
+		{[]string{"suggest", "go", "1.25", "--apply"}, "go.mod", "newest", nil, false},
+		{[]string{"suggest", "go", "1.22", "--apply"}, "README.md", "", errUsage, false},
+		{[]string{"suggest", "go", "1"}, "", "", errReleaseNotFound, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.golden, func(t *testing.T) {
+			t.Parallel()
+
+			args, target := tc.args, ""
+			if tc.file != "" {
+				dir := t.TempDir()
+				target = filepath.Join(dir, tc.file)
+				args = append(args, target)
+
+				content, err := os.ReadFile(filepath.Join("testdata", "suggest", tc.file))
+				if err != nil && !os.IsNotExist(err) {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				if err = os.WriteFile(target, content, 0o600); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			}
+
+			c, err := newClient(append(args, "--as-of", "2025-06-01"))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			buf := &bytes.Buffer{}
+			c.sink, c.errSink, c.httpClient = buf, &bytes.Buffer{}, &mockHTTPClient{}
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+
+			if tc.golden == "" {
+				return
+			}
+
+			out := bytes.ReplaceAll(buf.Bytes(), []byte(filepath.ToSlash(filepath.Dir(target))+"/"), nil)
+			if target != "" {
+				content, err := os.ReadFile(target)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				orig, err := os.ReadFile(filepath.Join("testdata", "suggest", tc.file))
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				if written := !bytes.Equal(content, orig); written != tc.expWrite {
+					t.Fatalf("Expected the file written %v, got %v:\n%s", tc.expWrite, written, content)
+				}
+
+				out = append(append(out, "\n--- "+tc.file+"\n"...), content...)
+			}
+
+			exp, err := os.ReadFile(filepath.Join("testdata", "suggest", tc.golden))
+			if err != nil {
+				t.Fatalf("Failed to read golden copy: %v", err)
+			}
+
+			if string(out) != string(exp) {
+				t.Fatalf("Expected\n%s\ngot\n%s", exp, out)
+			}
+		})
+	}
+}
+
+func TestRewriteVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct{ name, line, exp string }{
+		{"go.mod", "go 1.22", "go 1.24"},
+		{"go.mod", "go 1.22.3 // toolchain", "go 1.24.6 // toolchain"},
+		{".nvmrc", "v18", "v20"},
+		{".nvmrc", "18.17.0", "20.19.2"},
+		{"Dockerfile", "FROM --platform=linux/amd64 node:18.17-alpine AS build", "FROM --platform=linux/amd64 node:20.19-alpine AS build"},
+		{"app.Dockerfile", "FROM node:18@sha256:abc", "FROM node:18@sha256:abc"},
+		{"Dockerfile", "RUN echo 18", "RUN echo 18"},
+	}
+
+	for _, tc := range cases {
+		target := "20.19.2"
+		if tc.name == "go.mod" {
+			target = "1.24.6"
+		}
+
+		if act := rewriteVersion(tc.name, tc.line, target); act != tc.exp {
+			t.Fatalf("Expected %q, got %q", tc.exp, act)
+		}
+	}
+}
+
+func TestNewUpgrade(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	days := func(n int) *int { return &n }
+	cases := []struct {
+		eolFrom string
+		exp     *int
+	}{
+		{"2025-06-11", days(10)},
+		{"2025-02-11", days(0)}, // Past, like compare's supportDaysLeft.
+		{"", nil},
+	}
+
+	for _, tc := range cases {
+		u := newUpgrade(map[string]any{"name": "1", "eolFrom": tc.eolFrom}, now)
+		if !reflect.DeepEqual(u.SupportDays, tc.exp) {
+			t.Fatalf("Expected %v support days for %q, got %v", tc.exp, tc.eolFrom, u.SupportDays)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+module example.com/app
+
+go 1.22.4
+
+toolchain go1.22.5
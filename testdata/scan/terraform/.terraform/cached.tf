@@ -0,0 +1,3 @@
+resource "aws_eks_cluster" "cached" {
+  version = "1.20"
+}
@@ -0,0 +1,32 @@
+# Functions
+resource "aws_lambda_function" "api" {
+  function_name = "api"
+  runtime       = "python3.8" # TODO: upgrade
+  handler       = "main.handler"
+
+  environment {
+    variables = {
+      runtime = "nodejs22.x"
+    }
+  }
+}
+
+resource "aws_lambda_function" "worker" {
+  function_name = "worker"
+  runtime       = var.runtime
+}
+
+resource "aws_eks_cluster" "main" {
+  name    = "main"
+  version = "1.29"
+}
+
+resource "google_container_cluster" "gke" {
+  name               = "gke"
+  min_master_version = "1.31.6-gke.1020000"
+}
+
+resource "azurerm_kubernetes_cluster" "aks" {
+  name               = "aks"
+  kubernetes_version = "${var.aks_version}"
+}
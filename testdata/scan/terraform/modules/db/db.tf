@@ -0,0 +1,19 @@
+resource "aws_db_instance" "pg" {
+  engine         = "postgres"
+  engine_version = "11.22"
+}
+
+resource "aws_db_instance" "legacy" {
+  engine_version = "5.7.44"
+  engine         = "mysql"
+}
+
+resource "aws_rds_cluster" "aurora" {
+  engine         = "aurora-postgresql"
+  engine_version = "15.4"
+}
+
+resource "aws_opensearch_domain" "search" {
+  domain_name    = "search"
+  engine_version = "OpenSearch_1.3"
+}
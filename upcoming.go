@@ -0,0 +1,166 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// lifecycleEvent is a release reaching one of its lifecycle milestones.
+type lifecycleEvent struct {
+	Product      string `json:"product"`
+	ProductLabel string `json:"productLabel"`
+	Release      string `json:"release"`
+	ReleaseLabel string `json:"releaseLabel"`
+	Event        string `json:"event"`
+	Date         string `json:"date"`
+	Link         string `json:"link,omitempty"`
+}
+
+// Default window for the upcoming command.
+const defaultWithin = "90d"
+
+// Release date fields that mark a lifecycle event, and the event they mark.
+//
+//nolint:gochecknoglobals // ok
+var lifecycleFields = []struct{ field, event string }{
+	{"eoasFrom", "eoas"},
+	{"eolFrom", "eol"},
+	{"discontinuedFrom", "discontinued"},
+}
+
+func (c *client) upcoming() (err error) {
+	from, to, err := c.window()
+	if err != nil {
+		return
+	}
+
+	body, err := c.fetch("/products/full")
+	if err != nil {
+		return
+	}
+
+	var envelope struct {
+		Result []any `json:"result"`
+	}
+
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return
+	}
+
+	events := upcomingEvents(envelope.Result, from, to, c.productFilter())
+	c.response, err = json.Marshal(map[string]any{
+		"from": from.Format(time.DateOnly), "to": to.Format(time.DateOnly),
+		"total": len(events), "result": events,
+	})
+
+	return
+}
+
+// window returns the date range set via --from/--to/--within. The range
+// starts today (see --as-of) unless --from is given and, unless --to is given, spans
+// --within (defaultWithin if that's not set either).
+func (c *client) window() (from, to time.Time, err error) {
+	from = c.now().Truncate(24 * time.Hour) //nolint:mnd // ok
+
+	if c.from != "" {
+		if from, err = time.Parse(time.DateOnly, c.from); err != nil {
+			return from, to, fmt.Errorf("%w: invalid --from date: %w", errUsage, err)
+		}
+	}
+
+	if c.to != "" {
+		if to, err = time.Parse(time.DateOnly, c.to); err != nil {
+			return from, to, fmt.Errorf("%w: invalid --to date: %w", errUsage, err)
+		}
+
+		if to.Before(from) {
+			return from, to, fmt.Errorf("%w: --to %s is before --from %s", errUsage, c.to, from.Format(time.DateOnly))
+		}
+
+		return
+	}
+
+	dur, err := parseExtendedDuration(cmp.Or(c.within, defaultWithin))
+	if err != nil {
+		return from, to, fmt.Errorf("%w: invalid --within: %w", errUsage, err)
+	} else if dur < 0 {
+		return from, to, fmt.Errorf("%w: --within %s is negative", errUsage, c.within)
+	}
+
+	return from, from.Add(dur), nil
+}
+
+// productFilter returns a predicate for the --category, --tag and product
+// name (or alias) arguments. Products must satisfy all of the given ones.
+func (c *client) productFilter() func(product map[string]any) bool {
+	return func(p map[string]any) bool {
+		if c.category != "" && p["category"] != c.category {
+			return false
+		}
+
+		if c.tag != "" && !slices.Contains(toStringSlice(p["tags"]), c.tag) {
+			return false
+		}
+
+		if len(c.args) == 0 {
+			return true
+		}
+
+		return slices.Contains(c.args, getString(p, "name")) ||
+			slices.ContainsFunc(toStringSlice(p["aliases"]), func(a string) bool { return slices.Contains(c.args, a) })
+	}
+}
+
+// upcomingEvents lists the lifecycle events of all releases of the (filtered)
+// products that fall within [from, to], sorted by date.
+func upcomingEvents(products []any, from, to time.Time, filter func(map[string]any) bool) (events []lifecycleEvent) {
+	for _, p := range products {
+		product, ok := p.(map[string]any)
+		if !ok || (filter != nil && !filter(product)) {
+			continue
+		}
+
+		for _, r := range toSlice(product["releases"]) {
+			release, ok := r.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			for _, lf := range lifecycleFields {
+				date := getString(release, lf.field)
+
+				t, err := time.Parse(time.DateOnly, date)
+				if err != nil || t.Before(from) || t.After(to) {
+					continue
+				}
+
+				events = append(events, newLifecycleEvent(product, release, lf.event, date))
+			}
+		}
+	}
+
+	slices.SortStableFunc(events, func(a, b lifecycleEvent) int {
+		return cmp.Or(strings.Compare(a.Date, b.Date), strings.Compare(a.Product, b.Product),
+			compareVersions(a.Release, b.Release))
+	})
+
+	return
+}
+
+func newLifecycleEvent(product, release map[string]any, event, date string) lifecycleEvent {
+	links, _ := product["links"].(map[string]any) //nolint:errcheck // ok
+
+	return lifecycleEvent{
+		Product:      getString(product, "name"),
+		ProductLabel: getString(product, "label"),
+		Release:      getString(release, "name"),
+		ReleaseLabel: getString(release, "label"),
+		Event:        event,
+		Date:         date,
+		Link:         getString(links, "html"),
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestUpcomingEvents(t *testing.T) {
+	t.Parallel()
+
+	products := []any{
+		map[string]any{"name": "go", "category": "lang", "releases": []any{
+			map[string]any{"name": "1.24", "eolFrom": "2026-02-11"},
+			map[string]any{"name": "1.23", "eolFrom": "2025-08-12"},
+		}},
+		map[string]any{"name": "ubuntu", "category": "os", "releases": []any{
+			map[string]any{"name": "24.04", "eoasFrom": "2029-05-31", "eolFrom": "2029-05-31"},
+			map[string]any{"name": "22.04", "eoasFrom": "2024-09-30", "eolFrom": "2027-04-01"},
+			map[string]any{"name": "bogus", "eolFrom": "sometime"},
+		}},
+		map[string]any{"name": "nokia", "category": "device", "releases": []any{
+			map[string]any{"name": "c21", "discontinuedFrom": "2025-12-01"},
+		}},
+		"bogus",
+	}
+
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+
+	tests := []struct {
+		from, to string
+		filter   func(map[string]any) bool
+		exp      []string
+	}{
+		{"2025-01-01", "2025-12-31", nil, []string{"go 1.23 eol", "nokia c21 discontinued"}},
+		{"2025-08-12", "2025-08-12", nil, []string{"go 1.23 eol"}},
+		{"2026-01-01", "2030-01-01", nil, []string{"go 1.24 eol", "ubuntu 22.04 eol", "ubuntu 24.04 eoas", "ubuntu 24.04 eol"}},
+		{"2026-01-01", "2030-01-01", func(p map[string]any) bool { return p["category"] == "os" }, []string{
+			"ubuntu 22.04 eol", "ubuntu 24.04 eoas", "ubuntu 24.04 eol",
+		}},
+		{"2030-01-01", "2031-01-01", nil, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.from+"--"+tc.to, func(t *testing.T) {
+			t.Parallel()
+
+			var got []string
+			for _, e := range upcomingEvents(products, date(tc.from), date(tc.to), tc.filter) {
+				got = append(got, e.Product+" "+e.Release+" "+e.Event)
+			}
+
+			if !slices.Equal(got, tc.exp) {
+				t.Fatalf("expected %q, got %q", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestClientWindow(t *testing.T) {
+	t.Parallel()
+
+	c := &client{from: "2025-01-01", within: "10d"}
+
+	from, to, err := c.window()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if x, y := from.Format(time.DateOnly), to.Format(time.DateOnly); x != "2025-01-01" || y != "2025-01-11" {
+		t.Fatalf("Expected 2025-01-01..2025-01-11, got %s..%s", x, y)
+	}
+}
@@ -79,7 +79,12 @@ func toJSON(v any) string {
 	return string(b)
 }
 
-func eolWithin(duration string, eolDate any) (ok bool) {
+func eolWithin(duration string, eolDate any) bool {
+	return eolWithinAt(time.Now(), duration, eolDate)
+}
+
+// eolWithinAt is [eolWithin] as seen at the given point in time.
+func eolWithinAt(now time.Time, duration string, eolDate any) (ok bool) {
 	var err error
 
 	defer func() {
@@ -121,7 +126,6 @@ func eolWithin(duration string, eolDate any) (ok bool) {
 		return
 	}
 
-	now := time.Now()
 	futureLimit := now.Add(dur)
 
 	return eolTime.After(now) && eolTime.Before(futureLimit)
@@ -190,6 +194,18 @@ func collect(field string, slice any) (result []any) {
 	}
 }
 
+// getString returns m[key] if it is a string, "" otherwise.
+func getString(m map[string]any, key string) string {
+	s, _ := m[key].(string) //nolint:errcheck // ok
+	return s
+}
+
+// toSlice returns v if it is a []any, nil otherwise.
+func toSlice(v any) []any {
+	s, _ := v.([]any) //nolint:errcheck // ok
+	return s
+}
+
 func configDir(opts ...string) string {
 	var xs []string
 
@@ -206,3 +222,30 @@ func configDir(opts ...string) string {
 
 	return filepath.Join(append(xs, opts...)...)
 }
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev, curr := make([]int, len(rb)+1), make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
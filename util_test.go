@@ -269,6 +269,34 @@ func TestCollect(t *testing.T) {
 	}
 }
 
+func TestLevenshtein(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		exp  int
+	}{
+		{"", "", 0},
+		{"go", "", 2},
+		{"", "go", 2},
+		{"go", "go", 0},
+		{"golng", "golang", 1},
+		{"ubunut", "ubuntu", 2},
+		{"kitten", "sitting", 3},
+		{"über", "uber", 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.a+"--"+tc.b, func(t *testing.T) {
+			t.Parallel()
+
+			if got := levenshtein(tc.a, tc.b); got != tc.exp {
+				t.Fatalf("expected distance %d, got %d", tc.exp, got)
+			}
+		})
+	}
+}
+
 func TestConfigDir(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// releaseMatch describes how a user supplied version was matched to a release.
+type releaseMatch struct {
+	Query      string `json:"query"`
+	Normalized string `json:"normalized"`
+	Release    string `json:"release"`
+	Reason     string `json:"reason"`
+}
+
+//nolint:gochecknoglobals // ok
+var (
+	reVersionPrefix  = regexp.MustCompile(`^(?:v|version|release|go|jdk|java)[-_]?(\d)`)
+	reNumericVersion = regexp.MustCompile(`^\d+(?:\.\d+)*`)
+)
+
+// normalizeVersion strips the decorations commonly found around version
+// numbers: prefixes ("v1.22.3", "go1.22"), distro qualifiers ("22.04.3 LTS"),
+// build metadata ("17.0.9+9"), pre-release and variant suffixes ("1.22rc1",
+// "3.11.5-slim") and Java update notation ("8u392").
+func normalizeVersion(version string) string {
+	ver := strings.ToLower(strings.TrimSpace(version))
+	if i := strings.IndexAny(ver, " \t"); i > 0 {
+		ver = ver[:i]
+	}
+
+	ver, _, _ = strings.Cut(ver, "+")
+	ver = reVersionPrefix.ReplaceAllString(ver, "$1")
+
+	if m := reNumericVersion.FindString(ver); m != "" {
+		return m
+	}
+
+	return ver
+}
+
+// versionCandidates returns the release names to look for, most specific
+// first: the variants of the version as given, then those of its normalized
+// form (see [generateVersionVariants] and [normalizeVersion]).
+func versionCandidates(version string) (candidates []string) {
+	for _, v := range append(generateVersionVariants(version), generateVersionVariants(normalizeVersion(version))...) {
+		if !slices.Contains(candidates, v) {
+			candidates = append(candidates, v)
+		}
+	}
+
+	return
+}
+
+// matchRelease finds the release (from a product's releases list) that best
+// matches version, and reports how it got there.
+func matchRelease(releases []any, version string) (rel map[string]any, match releaseMatch, candidates []string) {
+	match = releaseMatch{Query: version, Normalized: normalizeVersion(version)}
+	candidates = versionCandidates(version)
+	raw := generateVersionVariants(version)
+
+	for _, candidate := range candidates {
+		for _, r := range releases {
+			r, ok := r.(map[string]any)
+			if name, _ := r["name"].(string); !ok || !strings.EqualFold(name, candidate) { //nolint:errcheck // ok
+				continue
+			}
+
+			match.Release = candidate
+
+			switch {
+			case candidate == version:
+				match.Reason = "exact match"
+			case slices.Contains(raw, candidate):
+				match.Reason = fmt.Sprintf("no release %q, fell back to %q", version, candidate)
+			case candidate == match.Normalized:
+				match.Reason = fmt.Sprintf("normalized %q to %q", version, candidate)
+			default:
+				match.Reason = fmt.Sprintf("normalized %q to %q, then fell back to %q",
+					version, match.Normalized, candidate)
+			}
+
+			return r, match, candidates
+		}
+	}
+
+	return nil, match, candidates
+}
+
+// lookupRelease fetches product pn and picks the release matching version.
+func (c *client) lookupRelease(pn, version string) (rel map[string]any, envelope map[string]any, err error) {
+	body, err := c.fetchAsOf("/products/" + pn)
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return
+	}
+
+	product, _ := envelope["result"].(map[string]any) //nolint:errcheck // ok
+	releases, _ := product["releases"].([]any)        //nolint:errcheck // ok
+
+	rel, match, candidates := matchRelease(releases, version)
+	if rel == nil {
+		return nil, nil, &ReleaseNotFoundError{Product: pn, Variants: candidates}
+	}
+
+	c.logf(LogVerbose, "release %s: picked %q (%s)", pn, match.Release, match.Reason)
+	rel["match"] = match
+
+	if behind, ok := patchesBehind(version, rel); ok {
+		rel["isOutdated"], rel["patchesBehind"] = behind > 0, behind
+	}
+
+	return
+}
+
+// release responds with the release of product pn that matches version,
+// shaped like the /products/{product}/releases/{release} endpoint.
+func (c *client) release(pn, version string) (err error) {
+	rel, envelope, err := c.lookupRelease(pn, version)
+	if err != nil {
+		return
+	}
+
+	envelope["result"] = rel
+	if c.response, err = json.Marshal(envelope); err != nil {
+		return
+	}
+
+	if outdated, _ := rel["isOutdated"].(bool); outdated && c.requireLatestPatch { //nolint:errcheck // ok
+		latest, _ := rel["latest"].(map[string]any) //nolint:errcheck // ok
+		latestName, _ := latest["name"].(string)    //nolint:errcheck // ok
+		link, _ := latest["link"].(string)          //nolint:errcheck // ok
+		behind, _ := rel["patchesBehind"].(int)     //nolint:errcheck // ok
+
+		c.deferredErr = &OutdatedError{Product: pn, Version: version, Latest: latestName, Link: link, Behind: behind}
+	}
+
+	return
+}
+
+// compareVersions compares two (normalized) versions segment by segment,
+// numerically where possible. Missing segments count as zero.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(normalizeVersion(a), "."), strings.Split(normalizeVersion(b), ".")
+
+	for i := range max(len(as), len(bs)) {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+
+		if i < len(bs) {
+			y = bs[i]
+		}
+
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+
+		switch {
+		case xerr == nil && yerr == nil && xn != yn:
+			return cmp.Compare(xn, yn)
+		case (xerr != nil || yerr != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+
+	return 0
+}
+
+// patchesBehind reports how far version is behind the latest version of
+// rel, i.e. the difference at the first segment where they differ
+// (1.24.3 vs 1.24.6 is 3 behind). It only applies (ok) when version is more
+// specific than the release name, otherwise there is no patch to compare.
+func patchesBehind(version string, rel map[string]any) (behind int, ok bool) {
+	name, _ := rel["name"].(string)             //nolint:errcheck // ok
+	latest, _ := rel["latest"].(map[string]any) //nolint:errcheck // ok
+	latestName, _ := latest["name"].(string)    //nolint:errcheck // ok
+	ver := normalizeVersion(version)
+
+	if latestName == "" || strings.Count(ver, ".") <= strings.Count(name, ".") {
+		return
+	}
+
+	if compareVersions(ver, latestName) >= 0 {
+		return 0, true
+	}
+
+	vs, ls := strings.Split(ver, "."), strings.Split(normalizeVersion(latestName), ".")
+	for i := range min(len(vs), len(ls)) {
+		x, xerr := strconv.Atoi(vs[i])
+		y, yerr := strconv.Atoi(ls[i])
+
+		if xerr == nil && yerr == nil && x != y {
+			return y - x, true
+		}
+	}
+
+	return 1, true
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version, exp string
+	}{
+		{"", ""},
+		{"1.24", "1.24"},
+		{"v1.22.3", "1.22.3"},
+		{"go1.22.3", "1.22.3"},
+		{"1.22rc1", "1.22"},
+		{"22.04.3 LTS", "22.04.3"},
+		{"3.11.5-slim", "3.11.5"},
+		{"17.0.9+9", "17.0.9"},
+		{"8u392", "8"},
+		{"jdk-21.0.1", "21.0.1"},
+		{"1.8.0_392", "1.8.0"},
+		{"provided.al2023", "provided.al2023"},
+		{"C21", "c21"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.version, func(t *testing.T) {
+			t.Parallel()
+
+			if got := normalizeVersion(tc.version); got != tc.exp {
+				t.Fatalf("expected %q, got %q", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestVersionCandidates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version string
+		exp     []string
+	}{
+		{"", nil},
+		{"1.24.6", []string{"1.24.6", "1.24", "1"}},
+		{"v1.22.3", []string{"v1.22.3", "v1.22", "v1", "1.22.3", "1.22", "1"}},
+		{"17.0.9+9", []string{"17.0.9+9", "17.0", "17", "17.0.9"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.version, func(t *testing.T) {
+			t.Parallel()
+
+			if got := versionCandidates(tc.version); !slices.Equal(got, tc.exp) {
+				t.Fatalf("expected %q, got %q", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestMatchRelease(t *testing.T) {
+	t.Parallel()
+
+	releases := []any{
+		map[string]any{"name": "1.25"},
+		map[string]any{"name": "1.24"},
+		map[string]any{"name": "provided.al2023"},
+		map[string]any{"name": "C21"},
+		"bogus",
+	}
+
+	tests := []struct {
+		version, expRelease, expReason string
+	}{
+		{"1.24", "1.24", "exact match"},
+		{"1.24.6", "1.24", `no release "1.24.6", fell back to "1.24"`},
+		{"go1.25", "1.25", `normalized "go1.25" to "1.25"`},
+		{"v1.24.3", "1.24", `normalized "v1.24.3" to "1.24.3", then fell back to "1.24"`},
+		{"provided.al2023", "provided.al2023", "exact match"},
+		{"c21", "C21", "exact match"},
+		{"1.99", "", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.version, func(t *testing.T) {
+			t.Parallel()
+
+			rel, match, candidates := matchRelease(releases, tc.version)
+			if tc.expRelease == "" {
+				if rel != nil || len(candidates) == 0 {
+					t.Fatalf("expected no match and some candidates, got %v, %v", rel, candidates)
+				}
+
+				return
+			}
+
+			if rel == nil || rel["name"] != tc.expRelease || match.Reason != tc.expReason {
+				t.Fatalf("expected %q (%s), got %v (%s)", tc.expRelease, tc.expReason, rel, match.Reason)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b string
+		exp  int
+	}{
+		{"1.24.3", "1.24.6", -1},
+		{"1.24.10", "1.24.9", 1},
+		{"1.24", "1.24.0", 0},
+		{"v1.24.6", "1.24.6", 0},
+		{"17.0.9+9", "17.0.10+7", -1},
+		{"22.04", "24.04", -1},
+		{"provided.al2", "provided.al2023", -1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.a+"--"+tc.b, func(t *testing.T) {
+			t.Parallel()
+
+			if got := compareVersions(tc.a, tc.b); got != tc.exp {
+				t.Fatalf("expected %d, got %d", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestPatchesBehind(t *testing.T) {
+	t.Parallel()
+
+	rel := func(name, latest string) map[string]any {
+		return map[string]any{"name": name, "latest": map[string]any{"name": latest}}
+	}
+
+	//nolint:govet // ok
+	tests := []struct {
+		version   string
+		rel       map[string]any
+		expBehind int
+		expOK     bool
+	}{
+		{"1.24.3", rel("1.24", "1.24.6"), 3, true},
+		{"1.24.6", rel("1.24", "1.24.6"), 0, true},
+		{"1.24.7", rel("1.24", "1.24.6"), 0, true},
+		{"1.24", rel("1.24", "1.24.6"), 0, false},
+		{"22.10.0", rel("22", "22.18.0"), 8, true},
+		{"21.0.1+12", rel("21", "21.0.8+9"), 7, true},
+		{"1.24.3", map[string]any{"name": "1.24"}, 0, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.version, func(t *testing.T) {
+			t.Parallel()
+
+			behind, ok := patchesBehind(tc.version, tc.rel)
+			if behind != tc.expBehind || ok != tc.expOK {
+				t.Fatalf("expected %d, %v, got %d, %v", tc.expBehind, tc.expOK, behind, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// watchConfig is the watch list, as read from --config, used by watch and digest.
+type watchConfig struct {
+	SMTP     smtpConfig     `json:"smtp"`
+	Within   string         `json:"within"`
+	Products []watchProduct `json:"products"`
+	Webhooks []webhook      `json:"webhooks"`
+}
+
+// watchProduct is a product to watch. Releases limits the status and EOL
+// window checks to the given releases (all of them, if empty).
+type watchProduct struct {
+	Product  string   `json:"product"`
+	Releases []string `json:"releases"`
+}
+
+// webhook is a notification target. Format selects the payload template,
+// webhook-<format> (generic, slack or teams), unless Template names another.
+type webhook struct {
+	URL      string `json:"url"`
+	Format   string `json:"format"`
+	Template string `json:"template"`
+}
+
+// watchEvent is a change worth notifying about.
+type watchEvent struct {
+	Product string `json:"product"`
+	Release string `json:"release"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	EolFrom string `json:"eolFrom,omitempty"`
+	Link    string `json:"link,omitempty"`
+}
+
+// watchState is the last seen state of every watched release, by product.
+type watchState map[string]map[string]watchedRelease
+
+type watchedRelease struct {
+	Status   string `json:"status"`
+	EolFrom  string `json:"eolFrom,omitempty"`
+	InWindow bool   `json:"inWindow,omitempty"`
+}
+
+// Watch event kinds.
+const (
+	EventNewRelease     = "new_release"
+	EventStatusChanged  = "status_changed"
+	EventEolApproaching = "eol_approaching"
+)
+
+// Default watch settings.
+const (
+	DefaultWatchInterval = "24h"
+	defaultWatchFormat   = "generic"
+)
+
+func (c *client) watch() (err error) {
+	cfg, err := readWatchConfig(c.config)
+	if err != nil {
+		return
+	}
+
+	interval, err := parseExtendedDuration(cmp.Or(c.interval, DefaultWatchInterval))
+	if err != nil {
+		return fmt.Errorf("%w: invalid --interval: %w", errUsage, err)
+	}
+
+	stateFile := cmp.Or(c.stateFile, configDir("watch-state.json"))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for {
+		if err = c.watchOnce(ctx, cfg, stateFile); err != nil && c.once {
+			return
+		} else if err != nil {
+			c.logf(LogNormal, "watch: %v", err)
+		}
+
+		if c.once {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// watchOnce polls all watched products, notifies about changes since the
+// last poll and persists the new state. Products failing to fetch are
+// skipped, and so are their events if a webhook fails: either way they keep
+// their previous state, so that the next poll retries them (the webhooks
+// that did get the events are notified again, then).
+func (c *client) watchOnce(ctx context.Context, cfg watchConfig, stateFile string) (err error) {
+	state, err := readWatchState(stateFile)
+	if err != nil {
+		return
+	}
+
+	window, err := parseExtendedDuration(cmp.Or(c.within, cfg.Within, defaultWithin))
+	if err != nil {
+		return fmt.Errorf("%w: invalid window: %w", errUsage, err)
+	}
+
+	var (
+		events   []watchEvent
+		fetchErr error
+	)
+
+	previous := watchState{}
+
+	for _, wp := range cfg.Products {
+		product, perr := c.fetchProduct(wp.Product)
+		if perr != nil {
+			fetchErr = errors.Join(fetchErr, fmt.Errorf("%s: %w", wp.Product, perr))
+
+			continue
+		}
+
+		var evs []watchEvent
+
+		previous[wp.Product] = state[wp.Product]
+		evs, state[wp.Product] = watchProductEvents(wp, product, state[wp.Product], c.now(), window)
+		events = append(events, evs...)
+	}
+
+	for _, e := range events {
+		c.logf(LogNormal, "watch: %s", e.Message)
+	}
+
+	var notifyErr error
+
+	if len(events) > 0 {
+		if notifyErr = c.notify(ctx, cfg.Webhooks, events); notifyErr != nil {
+			for _, e := range events {
+				state[e.Product] = previous[e.Product]
+			}
+		}
+	}
+
+	return errors.Join(fetchErr, notifyErr, writeWatchState(stateFile, state))
+}
+
+// fetchProduct fetches the named product, with its statuses as of --as-of.
+func (c *client) fetchProduct(name string) (product map[string]any, err error) {
+	body, err := c.fetchAsOf("/products/" + name)
+	if err != nil {
+		return
+	}
+
+	var envelope struct {
+		Result map[string]any `json:"result"`
+	}
+
+	err = json.Unmarshal(body, &envelope)
+
+	return envelope.Result, err
+}
+
+// watchProductEvents compares the product's releases with their previously
+// seen state and returns the resulting events along with the new state.
+// The first time a product is seen only its baseline state is recorded.
+func watchProductEvents(wp watchProduct, product map[string]any, prev map[string]watchedRelease,
+	now time.Time, window time.Duration,
+) (events []watchEvent, next map[string]watchedRelease) {
+	next = map[string]watchedRelease{}
+	releases := toSlice(product["releases"])
+	tracked := map[string]bool{}
+
+	for _, version := range wp.Releases {
+		if rel, _, _ := matchRelease(releases, version); rel != nil {
+			tracked[getString(rel, "name")] = true
+		}
+	}
+
+	for _, r := range releases {
+		rel, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		name := getString(rel, "name")
+		cur := watchedRelease{
+			Status:   releaseStatus(rel),
+			EolFrom:  getString(rel, "eolFrom"),
+			InWindow: dateWithin(now, window, getString(rel, "eolFrom")),
+		}
+		next[name] = cur
+
+		if prev == nil {
+			continue
+		}
+
+		old, seen := prev[name]
+		event := watchEvent{Product: wp.Product, Release: name, EolFrom: cur.EolFrom, Link: releaseLink(rel)}
+
+		switch {
+		case !seen:
+			event.Kind, event.Message = EventNewRelease, fmt.Sprintf("%s %s was released", wp.Product, name)
+		case len(tracked) > 0 && !tracked[name]:
+			continue
+		case old.Status != cur.Status:
+			event.Kind = EventStatusChanged
+			event.Message = fmt.Sprintf("%s %s is now %s (was %s)", wp.Product, name, cur.Status, old.Status)
+		case cur.InWindow && !old.InWindow:
+			event.Kind = EventEolApproaching
+			event.Message = fmt.Sprintf("%s %s reaches EOL on %s", wp.Product, name, cur.EolFrom)
+		default:
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	return
+}
+
+// notify posts the events to every webhook, returning the failures.
+func (c *client) notify(ctx context.Context, hooks []webhook, events []watchEvent) (err error) {
+	for _, hook := range hooks {
+		if hookErr := c.postWebhook(ctx, hook, events); hookErr != nil {
+			err = errors.Join(err, fmt.Errorf("webhook %s: %w", hook.URL, hookErr))
+		}
+	}
+
+	return
+}
+
+func (c *client) postWebhook(ctx context.Context, hook webhook, events []watchEvent) (err error) {
+	payload, err := c.renderTemplate(cmp.Or(hook.Template, "webhook-"+cmp.Or(hook.Format, defaultWatchFormat)),
+		map[string]any{"events": events, "generatedAt": c.now().UTC().Format(time.RFC3339)})
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("User-Agent", userAgent+"/"+version)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errNetwork, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // ok
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &APIError{URL: hook.URL, StatusCode: resp.StatusCode}
+	}
+
+	return
+}
+
+// renderTemplate executes the named template against data, after a JSON
+// round trip, so templates see the same shapes as for API responses.
+func (c *client) renderTemplate(name string, data any) (out []byte, err error) {
+	tmpl := c.templates.Lookup(name)
+	if tmpl == nil {
+		return nil, fmt.Errorf("template %s %w", name, errNotFound)
+	}
+
+	v, err := roundTripJSON(data)
+	if err != nil {
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	err = tmpl.Execute(buf, v)
+
+	return buf.Bytes(), err
+}
+
+// roundTripJSON returns data as decoded from its JSON encoding.
+func roundTripJSON(data any) (v any, err error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(b, &v)
+
+	return
+}
+
+func readWatchConfig(fname string) (cfg watchConfig, err error) {
+	if fname == "" {
+		return cfg, fmt.Errorf("%w: watch requires --config <watchlist.json>", errUsage)
+	}
+
+	body, err := os.ReadFile(fname) //nolint:gosec // user supplied path, on purpose
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(body, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid watch config %s: %w", fname, err)
+	}
+
+	if len(cfg.Products) == 0 {
+		return cfg, fmt.Errorf("%w: watch config %s lists no products", errUsage, fname)
+	}
+
+	if slices.ContainsFunc(cfg.Products, func(wp watchProduct) bool { return wp.Product == "" }) {
+		return cfg, fmt.Errorf("%w: watch config %s has an entry without product", errUsage, fname)
+	}
+
+	return
+}
+
+func readWatchState(fname string) (state watchState, err error) {
+	state = watchState{}
+
+	body, err := os.ReadFile(fname) //nolint:gosec // ok
+	if os.IsNotExist(err) {
+		return state, nil
+	} else if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(body, &state)
+
+	return
+}
+
+func writeWatchState(fname string, state watchState) (err error) {
+	if err = os.MkdirAll(filepath.Dir(fname), 0o750); err != nil { //nolint:mnd // ok
+		return
+	}
+
+	body, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return
+	}
+
+	return os.WriteFile(fname, body, 0o640) //nolint:mnd // ok
+}
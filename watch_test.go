@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingHTTPClient records the bodies POSTed to it, by URL path, unless
+// it fails them with status, and serves everything else from the golden copies.
+type recordingHTTPClient struct {
+	posts map[string][]byte
+	mockHTTPClient
+	status int
+	mu     sync.Mutex
+}
+
+func (m *recordingHTTPClient) Do(r *http.Request) (*http.Response, error) {
+	if r.Method != http.MethodPost {
+		return m.mockHTTPClient.Do(r)
+	}
+
+	if m.status != 0 {
+		return &http.Response{StatusCode: m.status, Body: io.NopCloser(&bytes.Buffer{})}, nil
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.posts[path.Base(r.URL.Path)] = body
+	m.mu.Unlock()
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(&bytes.Buffer{})}, nil
+}
+
+func TestClientWatch(t *testing.T) {
+	t.Parallel()
+
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	args := []string{
+		"watch", "--config", filepath.Join("testdata", "watch", "watchlist.json"),
+		"--state", stateFile, "--as-of", "2025-06-01", "--once",
+	}
+	hc := &recordingHTTPClient{posts: map[string][]byte{}}
+
+	run := func() error {
+		t.Helper()
+
+		c, err := newClient(args)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		c.sink, c.errSink, c.httpClient = &bytes.Buffer{}, &bytes.Buffer{}, hc
+
+		return c.handle()
+	}
+
+	if err := run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(hc.posts) != 0 {
+		t.Fatalf("Expected no notifications for the baseline run, got %d", len(hc.posts))
+	}
+
+	state, err := readWatchState(stateFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	delete(state["go"], "1.25")
+	state["go"]["1.24"] = watchedRelease{Status: StatusEol}
+	state["go"]["1.23"] = watchedRelease{Status: StatusMaintained, EolFrom: "2025-08-12"}
+	state["go"]["1.22"] = watchedRelease{Status: StatusMaintained} // not tracked, no event
+
+	if err = writeWatchState(stateFile, state); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The webhooks are down: the events must be kept for the next run.
+	hc.status = http.StatusInternalServerError
+
+	var apiErr *APIError
+	if err = run(); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("Expected a 500 APIError, got %v", err)
+	}
+
+	if kept, err := readWatchState(stateFile); err != nil || !reflect.DeepEqual(kept, state) {
+		t.Fatalf("Expected the state to be kept, got %v (%v)", kept, err)
+	}
+
+	hc.status = 0
+
+	if err = run(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"generic", "slack", "teams"} {
+		got := hc.posts[name]
+		if !json.Valid(got) {
+			t.Fatalf("Expected valid JSON payload for %s, got %q", name, got)
+		}
+
+		exp, err := os.ReadFile(filepath.Join("testdata", "watch", name))
+		if err != nil {
+			t.Fatalf("Failed to read golden copy: %v", err)
+		}
+
+		if !bytes.Equal(got, exp) {
+			t.Fatalf("Expected %s payload %q, got %q", name, exp, got)
+		}
+	}
+}
+
+func TestClientWatchErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		args   []string
+		expErr error
+	}{
+		{[]string{"watch"}, errUsage},
+		{[]string{"watch", "--config", "testdata/watch/watchlist.json", "--interval", "soon"}, errUsage},
+		{[]string{"watch", "--config", "testdata/watch/missing.json"}, os.ErrNotExist},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expErr.Error(), func(t *testing.T) {
+			t.Parallel()
+
+			c, err := newClient(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if err = c.handle(); !errors.Is(err, tc.expErr) {
+				t.Fatalf("Expected error %v, got %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestWatchProductEvents(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	product := map[string]any{"releases": []any{
+		map[string]any{"name": "2", "isMaintained": true},
+		map[string]any{"name": "1", "isMaintained": true, "eolFrom": "2025-07-01"},
+	}}
+	wp := watchProduct{Product: "x"}
+
+	events, state := watchProductEvents(wp, product, nil, now, 90*24*time.Hour)
+	if len(events) != 0 || len(state) != 2 || !state["1"].InWindow {
+		t.Fatalf("Unexpected baseline: %v %v", events, state)
+	}
+
+	events, _ = watchProductEvents(wp, product, state, now, 90*24*time.Hour)
+	if len(events) != 0 {
+		t.Fatalf("Expected no events for an unchanged product, got %v", events)
+	}
+
+	delete(state, "2")
+	state["1"] = watchedRelease{Status: StatusMaintained}
+
+	events, _ = watchProductEvents(wp, product, state, now, 90*24*time.Hour)
+	if len(events) != 2 || events[0].Kind != EventNewRelease || events[1].Kind != EventEolApproaching {
+		t.Fatalf("Unexpected events: %v", events)
+	}
+}
+
+func TestClientWatchFetchError(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	stateFile, config := filepath.Join(dir, "state.json"), filepath.Join(dir, "watchlist.json")
+	hc := &recordingHTTPClient{posts: map[string][]byte{}}
+
+	cfg := `{"products": [{"product": "golng"}, {"product": "go", "releases": ["1.24"]}],` +
+		`"webhooks": [{"url": "https://hooks.example.com/generic"}]}`
+	if err := os.WriteFile(config, []byte(cfg), 0o600); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stale := watchState{"golng": {"1": {Status: StatusMaintained}}, "go": {"1.24": {Status: StatusEol}}}
+	if err := writeWatchState(stateFile, stale); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	c, err := newClient([]string{"watch", "--config", config, "--state", stateFile, "--as-of", "2025-06-01", "--once"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	c.sink, c.errSink, c.httpClient = &bytes.Buffer{}, &bytes.Buffer{}, hc
+
+	// One failing product must not hold back the others' events.
+	if err = c.handle(); !errors.Is(err, errNotFound) {
+		t.Fatalf("Expected error %v, got %v", errNotFound, err)
+	}
+
+	if len(hc.posts) != 1 {
+		t.Fatalf("Expected the go events to be delivered, got %d posts", len(hc.posts))
+	}
+
+	state, err := readWatchState(stateFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(state["golng"], stale["golng"]) || state["go"]["1.24"].Status == StatusEol {
+		t.Fatalf("Expected golng's state kept and go's updated, got %v", state)
+	}
+}